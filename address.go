@@ -0,0 +1,361 @@
+package zipcode
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	// 住所から市区町村を見つけられない場合のエラー
+	errUnknownMunicipality = errors.New("unknown municipality")
+)
+
+// 住所を表す。
+type Address struct {
+	// 都道府県名。
+	Pref Name
+
+	// 市区町村名。
+	Region Name
+
+	// 町域名。一覧にない町域名はカナ表記を持たない。
+	Town Name
+
+	// 丁目、番地、号。それぞれ算用数字で、なければ空。
+	Chome  string
+	Banchi string
+	Go     string
+
+	// 番地以降の建物名など。
+	Building string
+
+	// 住所に該当するエントリ。
+	Entries []*Entry
+}
+
+// Stringは"東京都新宿区西新宿2丁目8番1号 都庁第一本庁舎"の形式に正規化した住所を返す。
+func (addr *Address) String() string {
+	var buf strings.Builder
+	buf.WriteString(addr.Pref.Text)
+	buf.WriteString(addr.Region.Text)
+	buf.WriteString(addr.Town.Text)
+	buf.WriteString(addr.Street().String())
+	if addr.Building != "" {
+		buf.WriteString(" " + addr.Building)
+	}
+	return buf.String()
+}
+
+// Streetは丁目、番地、号と建物名を返す。
+func (addr *Address) Street() Street {
+	return Street{
+		Chome:    addr.Chome,
+		Banchi:   addr.Banchi,
+		Go:       addr.Go,
+		Building: addr.Building,
+	}
+}
+
+// gazetteerは住所の解析に使う市区町村と町域の一覧。
+type gazetteer struct {
+	regions []*gazRegion
+	towns   map[string][]*Entry
+}
+
+// gazRegionは市区町村と、住所に書かれうる名前。
+type gazRegion struct {
+	entry *Entry
+	names []string
+}
+
+func newGazetteer(entries []*Entry) *gazetteer {
+	g := &gazetteer{towns: make(map[string][]*Entry)}
+	for _, entry := range entries {
+		if _, ok := g.towns[entry.Code]; !ok {
+			names := []string{entry.Region.Text}
+			// 郡は省略されることがある。
+			if s := entry.City.Text + entry.Ward.Text; s != "" && s != entry.Region.Text {
+				names = append(names, s)
+			}
+			g.regions = append(g.regions, &gazRegion{entry: entry, names: names})
+		}
+		g.towns[entry.Code] = append(g.towns[entry.Code], entry)
+	}
+	return g
+}
+
+func (idx *Index) gazetteer() *gazetteer {
+	idx.gazOnce.Do(func() {
+		idx.gaz = newGazetteer(idx.entries)
+	})
+	return idx.gaz
+}
+
+// ParseAddressは自由形式の住所sを都道府県、市区町村、町域、丁目、番地、号、建物名に分ける。
+//
+// 都道府県名は省略してもよい。丁目などは漢数字や全角数字で書かれていてもよく、
+// "2-8-1"のように区切り文字で書かれていてもよい。
+func (idx *Index) ParseAddress(s string) (*Address, error) {
+	g := idx.gazetteer()
+	candidates, t := g.findRegions(normalizeAddressInput(s))
+	if len(candidates) == 0 {
+		return nil, errUnknownMunicipality
+	}
+
+	var (
+		addr   *Address
+		townN  = -1
+		region *gazRegion
+	)
+	for _, r := range candidates {
+		entries, m := matchTown(g.towns[r.entry.Code], t)
+		if m > townN {
+			townN = m
+			region = r
+			addr = &Address{Entries: entries}
+		}
+	}
+	addr.Pref = region.entry.Pref
+	addr.Region = region.entry.Region
+	if townN > 0 {
+		addr.Town = addr.Entries[0].Town
+	} else {
+		// 一覧にない町域名は、数字の前までとみなす。
+		townN = strings.IndexFunc(t, isDigit)
+		if townN < 0 {
+			townN = len(t)
+		}
+		addr.Town = Name{Text: strings.TrimSpace(t[:townN])}
+	}
+	t = trimLeftSpace(t[townN:])
+
+	// 展開された町域名が丁目を含む場合は分ける。
+	if text, chome := splitChome(addr.Town.Text); chome != "" {
+		addr.Town.Text = text
+		addr.Chome = chome
+	}
+	parseStreet(addr, t, isBlocked(addr.Entries))
+	return addr, nil
+}
+
+// findRegionsは住所tの先頭に最も長く一致する市区町村と、残りの文字列を返す。
+// 都道府県名は省略してもよい。都道府県、市区町村、町域の間の空白は無視する。
+func (g *gazetteer) findRegions(t string) ([]*gazRegion, string) {
+	t = trimLeftSpace(t)
+	var pref string
+	for _, p := range prefectures {
+		if strings.HasPrefix(t, p.Name.Text) {
+			pref = p.Name.Text
+			t = trimLeftSpace(t[len(pref):])
+			break
+		}
+	}
+
+	var (
+		candidates []*gazRegion
+		n          int
+	)
+	for _, r := range g.regions {
+		if pref != "" && r.entry.Pref.Text != pref {
+			continue
+		}
+		for _, name := range r.names {
+			if !strings.HasPrefix(t, name) || len(name) < n {
+				continue
+			}
+			if len(name) > n {
+				candidates = candidates[:0]
+				n = len(name)
+			}
+			candidates = append(candidates, r)
+			break
+		}
+	}
+	return candidates, trimLeftSpace(t[n:])
+}
+
+func trimLeftSpace(s string) string {
+	return strings.TrimLeftFunc(s, unicode.IsSpace)
+}
+
+// matchTownはentriesのうち町域名がsの先頭と最も長く一致するものと、一致した長さを返す。
+// 一致するものがなければ、町域名を持たないエントリを返す。
+func matchTown(entries []*Entry, s string) ([]*Entry, int) {
+	var (
+		a []*Entry
+		n int
+	)
+	for _, entry := range entries {
+		town := entry.Town.Text
+		if town == "" || !strings.HasPrefix(s, town) || len(town) < n {
+			continue
+		}
+		// "町域1"は"町域10"に一致させない。
+		if endsWithDigit(town) && len(s) > len(town) && isDigit(rune(s[len(town)])) {
+			continue
+		}
+		if len(town) > n {
+			a = a[:0]
+			n = len(town)
+		}
+		a = append(a, entry)
+	}
+	if n > 0 {
+		return a, n
+	}
+	for _, entry := range entries {
+		if entry.Town.Text == "" {
+			a = append(a, entry)
+		}
+	}
+	return a, 0
+}
+
+func isBlocked(entries []*Entry) bool {
+	for _, entry := range entries {
+		if entry.IsBlockedScheme {
+			return true
+		}
+	}
+	return false
+}
+
+// splitChomeは"旭ケ丘1丁目"を"旭ケ丘"と"1"に分ける。
+func splitChome(s string) (string, string) {
+	const suffix = "丁目"
+	if !strings.HasSuffix(s, suffix) {
+		return s, ""
+	}
+	t := s[:len(s)-len(suffix)]
+	i := len(t)
+	for i > 0 && isDigit(rune(t[i-1])) {
+		i--
+	}
+	if i == len(t) || i == 0 {
+		return s, ""
+	}
+	return t[:i], t[i:]
+}
+
+// streetMarkersは丁目、番地、号をあらわす文字列。長いものから順に並べる。
+var streetMarkers = []string{"丁目", "番地", "番", "号", "の", "-"}
+
+// parseStreetは町域名より後の丁目、番地、号、建物名をaddrにセットする。
+// blockedなら、区切り文字だけで書かれた最初の数字を丁目とみなす。
+// 数字の前の空白は無視して、丁目、番地、号より後を建物名とする。
+func parseStreet(addr *Address, s string, blocked bool) {
+	type part struct {
+		num  string
+		mark string
+	}
+	var parts []part
+	t := []rune(s)
+	for len(t) > 0 && len(parts) < 3 {
+		if u := []rune(trimLeftSpace(string(t))); len(u) > 0 && (isDigit(u[0]) || isKanjiNumeral(u[0])) {
+			t = u
+		}
+		i := 0
+		for i < len(t) && (isDigit(t[i]) || isKanjiNumeral(t[i])) {
+			i++
+		}
+		if i == 0 {
+			break
+		}
+		num := string(t[:i])
+		if !isDigits(num) {
+			v, ok := parseKanjiNumber(t[:i])
+			if !ok {
+				break
+			}
+			num = strconv.Itoa(v)
+		}
+		t = t[i:]
+		var mark string
+		for _, m := range streetMarkers {
+			if strings.HasPrefix(string(t), m) {
+				mark = m
+				t = t[len([]rune(m)):]
+				break
+			}
+		}
+		parts = append(parts, part{num, mark})
+		if mark == "" || mark == "号" {
+			break
+		}
+	}
+	addr.Building = strings.TrimFunc(string(t), func(c rune) bool {
+		return unicode.IsSpace(c) || c == '-'
+	})
+	if len(parts) == 0 {
+		return
+	}
+
+	fields := []*string{&addr.Banchi, &addr.Go}
+	switch {
+	case addr.Chome != "":
+	case parts[0].mark == "丁目":
+		fields = []*string{&addr.Chome, &addr.Banchi, &addr.Go}
+	case parts[0].mark == "番" || parts[0].mark == "番地":
+	case len(parts) == 3 || blocked:
+		fields = []*string{&addr.Chome, &addr.Banchi, &addr.Go}
+	}
+	for i, p := range parts {
+		if i < len(fields) {
+			*fields[i] = p.num
+		}
+	}
+}
+
+// dashesは区切り文字として使われるハイフンの異体。
+var dashes = map[rune]bool{
+	'-': true, '－': true, '−': true, '‐': true, '‑': true,
+	'–': true, '—': true, '―': true, 'ｰ': true, 'ー': true,
+}
+
+// normalizeAddressInputは入力された住所の英数字や記号をASCII文字に統一する。
+// 長音記号は数字の直後にある場合だけハイフンとみなす。
+// 丁目の前の漢数字は算用数字にする。
+func normalizeAddressInput(s string) string {
+	r := []rune(normalizeText(strings.TrimSpace(s)))
+	var buf strings.Builder
+	for i := 0; i < len(r); i++ {
+		c := r[i]
+		switch {
+		case c == '　':
+			c = ' '
+		case c == 'ー' || c == 'ｰ':
+			if i > 0 && (isDigit(r[i-1]) || isKanjiNumeral(r[i-1])) {
+				c = '-'
+			}
+		case dashes[c]:
+			c = '-'
+		case c >= 'Ａ' && c <= 'Ｚ', c >= 'ａ' && c <= 'ｚ':
+			c = c - 'Ａ' + 'A'
+		}
+		if isKanjiNumeral(c) {
+			j := i
+			for j < len(r) && isKanjiNumeral(r[j]) {
+				j++
+			}
+			if strings.HasPrefix(string(r[j:]), "丁目") {
+				if v, ok := parseKanjiNumber(r[i:j]); ok {
+					buf.WriteString(strconv.Itoa(v))
+					i = j - 1
+					continue
+				}
+			}
+		}
+		buf.WriteRune(c)
+	}
+	return buf.String()
+}
+
+func isDigit(c rune) bool {
+	return c >= '0' && c <= '9'
+}
+
+func endsWithDigit(s string) bool {
+	return s != "" && isDigit(rune(s[len(s)-1]))
+}
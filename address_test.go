@@ -0,0 +1,118 @@
+package zipcode
+
+import (
+	"testing"
+)
+
+func TestParseAddress(t *testing.T) {
+	idx := testIndex(t)
+	tests := []struct {
+		s      string
+		expect Address
+		zip    Zip
+		str    string
+	}{
+		{
+			s: "東京都新宿区西新宿２−８−１　都庁第一本庁舎",
+			expect: Address{
+				Pref:     Name{"東京都", "ﾄｳｷｮｳﾄ"},
+				Region:   Name{"新宿区", "ｼﾝｼﾞｭｸｸ"},
+				Town:     Name{"西新宿", "ﾆｼｼﾝｼﾞｭｸ"},
+				Chome:    "2",
+				Banchi:   "8",
+				Go:       "1",
+				Building: "都庁第一本庁舎",
+			},
+			zip: "1600023",
+			str: "東京都新宿区西新宿2丁目8番1号 都庁第一本庁舎",
+		},
+		{
+			s: "東京都 新宿区 西新宿2-8-1 都庁 第一本庁舎",
+			expect: Address{
+				Pref:     Name{"東京都", "ﾄｳｷｮｳﾄ"},
+				Region:   Name{"新宿区", "ｼﾝｼﾞｭｸｸ"},
+				Town:     Name{"西新宿", "ﾆｼｼﾝｼﾞｭｸ"},
+				Chome:    "2",
+				Banchi:   "8",
+				Go:       "1",
+				Building: "都庁 第一本庁舎",
+			},
+			zip: "1600023",
+			str: "東京都新宿区西新宿2丁目8番1号 都庁 第一本庁舎",
+		},
+		{
+			s: "新宿区　西新宿 2丁目 8番 1号",
+			expect: Address{
+				Pref:   Name{"東京都", "ﾄｳｷｮｳﾄ"},
+				Region: Name{"新宿区", "ｼﾝｼﾞｭｸｸ"},
+				Town:   Name{"西新宿", "ﾆｼｼﾝｼﾞｭｸ"},
+				Chome:  "2",
+				Banchi: "8",
+				Go:     "1",
+			},
+			zip: "1600023",
+			str: "東京都新宿区西新宿2丁目8番1号",
+		},
+		{
+			s: "新宿区西新宿2丁目8番1号",
+			expect: Address{
+				Pref:   Name{"東京都", "ﾄｳｷｮｳﾄ"},
+				Region: Name{"新宿区", "ｼﾝｼﾞｭｸｸ"},
+				Town:   Name{"西新宿", "ﾆｼｼﾝｼﾞｭｸ"},
+				Chome:  "2",
+				Banchi: "8",
+				Go:     "1",
+			},
+			zip: "1600023",
+			str: "東京都新宿区西新宿2丁目8番1号",
+		},
+		{
+			s: "札幌市中央区旭ケ丘三丁目十五番地",
+			expect: Address{
+				Pref:   Name{"北海道", "ﾎｯｶｲﾄﾞｳ"},
+				Region: Name{"札幌市中央区", "ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ"},
+				Town:   Name{"旭ケ丘", "ｱｻﾋｶﾞｵｶ"},
+				Chome:  "3",
+				Banchi: "15",
+			},
+			zip: "0640941",
+			str: "北海道札幌市中央区旭ケ丘3丁目15番地",
+		},
+		{
+			s: "新宿区歌舞伎町1ー2ー3",
+			expect: Address{
+				Pref:   Name{"東京都", "ﾄｳｷｮｳﾄ"},
+				Region: Name{"新宿区", "ｼﾝｼﾞｭｸｸ"},
+				Town:   Name{"歌舞伎町", ""},
+				Chome:  "1",
+				Banchi: "2",
+				Go:     "3",
+			},
+			zip: "1600000",
+			str: "東京都新宿区歌舞伎町1丁目2番3号",
+		},
+	}
+	for _, tt := range tests {
+		addr, err := idx.ParseAddress(tt.s)
+		if err != nil {
+			t.Errorf("ParseAddress(%q) = %v", tt.s, err)
+			continue
+		}
+		e := tt.expect
+		if !addr.Pref.Equal(e.Pref) || !addr.Region.Equal(e.Region) || !addr.Town.Equal(e.Town) {
+			t.Errorf("ParseAddress(%q) = %q %q %q; Expect %q %q %q", tt.s, addr.Pref, addr.Region, addr.Town, e.Pref, e.Region, e.Town)
+		}
+		if addr.Chome != e.Chome || addr.Banchi != e.Banchi || addr.Go != e.Go || addr.Building != e.Building {
+			t.Errorf("ParseAddress(%q) = %q %q %q %q; Expect %q %q %q %q", tt.s, addr.Chome, addr.Banchi, addr.Go, addr.Building, e.Chome, e.Banchi, e.Go, e.Building)
+		}
+		if len(addr.Entries) != 1 || addr.Entries[0].Zip != tt.zip {
+			t.Errorf("ParseAddress(%q).Entries = %v; Expect %s", tt.s, addr.Entries, tt.zip)
+		}
+		if s := addr.String(); s != tt.str {
+			t.Errorf("ParseAddress(%q).String() = %q; Expect %q", tt.s, s, tt.str)
+		}
+	}
+	if _, err := idx.ParseAddress("大阪府大阪市北区"); err == nil {
+		t.Errorf("ParseAddress(%q) = nil; Expect an error", "大阪府大阪市北区")
+	}
+}
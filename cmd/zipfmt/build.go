@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"lufia.org/pkg/japanese/zipcode"
+)
+
+func runBuild(args []string) {
+	fs := flag.NewFlagSet("build", flag.ExitOnError)
+	file := fs.String("f", dataFile(), "read KEN_ALL.CSV from `file` instead of stdin; default $ZIPFMT_DATA")
+	out := fs.String("o", "", "write the snapshot to `file`")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s build [options] -o snapshot\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 0 || *out == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	idx, err := loadIndex(*file)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fout, err := os.Create(*out)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if err := zipcode.WriteSnapshot(fout, idx.Entries()); err != nil {
+		fout.Close()
+		log.Fatalln(err)
+	}
+	if err := fout.Close(); err != nil {
+		log.Fatalln(err)
+	}
+}
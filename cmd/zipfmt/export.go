@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"lufia.org/pkg/japanese/zipcode"
+)
+
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	file := fs.String("f", dataFile(), "read KEN_ALL.CSV or snapshot from `file` instead of stdin; default $ZIPFMT_DATA")
+	dialect := fs.String("sql", "postgres", "SQL `dialect`: postgres, mysql or sqlite")
+	batch := fs.Int("batch", 0, "number of rows per INSERT statement")
+	useCopy := fs.Bool("copy", false, "use COPY instead of INSERT for postgres")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s export [options]\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	d, err := zipcode.ParseDialect(*dialect)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	w := zipcode.SQLWriter{
+		Dialect:   d,
+		BatchSize: *batch,
+		UseCopy:   *useCopy,
+	}
+	if err := w.Begin(os.Stdout); err != nil {
+		log.Fatalln(err)
+	}
+	// 読み込みに失敗した場合はCommitを出力せずに終了する。
+	if err := exportEntries(&w, *file); err != nil {
+		log.Fatalln(err)
+	}
+	if err := w.Commit(); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// exportEntriesはfileのエントリを1つずつwへ渡す。
+// fileが空文字列なら標準入力から読む。
+func exportEntries(w *zipcode.SQLWriter, file string) error {
+	r := os.Stdin
+	if file != "" {
+		fin, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer fin.Close()
+		r = fin
+	}
+	fin := bufio.NewReader(r)
+	b, _ := fin.Peek(4)
+	if zipcode.IsSnapshot(b) {
+		entries, err := zipcode.LoadSnapshot(fin)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := w.WriteEntry(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	var p zipcode.Parser
+	c := p.Parse(fin)
+	for entry := range c {
+		if err := w.WriteEntry(entry); err != nil {
+			// 残りを読み捨ててParseのgoroutineを終わらせる。
+			for range c {
+			}
+			return err
+		}
+	}
+	return p.Error
+}
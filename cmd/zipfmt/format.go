@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"lufia.org/pkg/japanese/zipcode"
+)
+
+// formatterはエントリを特定の書式で出力する。
+type formatter interface {
+	Write(entry *zipcode.Entry) error
+
+	// Closeは出力を完了させる。wはcloseしない。
+	Close() error
+}
+
+// newFormatterはnameで指定された書式でwへ出力するformatterを返す。
+func newFormatter(w io.Writer, name string) (formatter, error) {
+	switch name {
+	case "text":
+		return &textFormatter{w: bufio.NewWriter(w)}, nil
+	case "json":
+		return &jsonFormatter{w: bufio.NewWriter(w)}, nil
+	case "jsonl":
+		return &jsonlFormatter{w: bufio.NewWriter(w)}, nil
+	case "csv":
+		return newCSVFormatter(w, ','), nil
+	case "tsv":
+		return newCSVFormatter(w, '\t'), nil
+	default:
+		return nil, fmt.Errorf("unknown format: %s", name)
+	}
+}
+
+// recordはJSONで出力するエントリの形式。
+type record struct {
+	Code            string `json:"code"`
+	OldZip          string `json:"old_zip"`
+	Zip             string `json:"zip"`
+	Pref            name   `json:"pref"`
+	Region          name   `json:"region"`
+	District        name   `json:"district"`
+	City            name   `json:"city"`
+	Ward            name   `json:"ward"`
+	Town            name   `json:"town"`
+	IsPartialTown   bool   `json:"is_partial_town"`
+	IsLargeTown     bool   `json:"is_large_town"`
+	IsBlockedScheme bool   `json:"is_blocked_scheme"`
+	IsOverlappedZip bool   `json:"is_overlapped_zip"`
+	Status          int    `json:"status"`
+	Reason          int    `json:"reason"`
+	Notice          string `json:"notice"`
+}
+
+type name struct {
+	Text string `json:"text"`
+	Ruby string `json:"ruby"`
+}
+
+func newRecord(entry *zipcode.Entry) *record {
+	return &record{
+		Code:            entry.Code,
+		OldZip:          string(entry.OldZip),
+		Zip:             string(entry.Zip),
+		Pref:            name(entry.Pref),
+		Region:          name(entry.Region),
+		District:        name(entry.District),
+		City:            name(entry.City),
+		Ward:            name(entry.Ward),
+		Town:            name(entry.Town),
+		IsPartialTown:   entry.IsPartialTown,
+		IsLargeTown:     entry.IsLargeTown,
+		IsBlockedScheme: entry.IsBlockedScheme,
+		IsOverlappedZip: entry.IsOverlappedZip,
+		Status:          int(entry.Status),
+		Reason:          int(entry.Reason),
+		Notice:          entry.Notice,
+	}
+}
+
+type textFormatter struct {
+	w *bufio.Writer
+}
+
+func (f *textFormatter) Write(v *zipcode.Entry) error {
+	_, err := fmt.Fprintf(f.w, "%s %s%s%s %s%s%s\n", v.Zip,
+		v.Pref.Text, v.Region.Text, v.Town.Text,
+		v.Pref.Ruby, v.Region.Ruby, v.Town.Ruby)
+	return err
+}
+
+func (f *textFormatter) Close() error {
+	return f.w.Flush()
+}
+
+// jsonFormatterはすべてのエントリを1つのJSON配列として出力する。
+type jsonFormatter struct {
+	w *bufio.Writer
+	n int
+}
+
+func (f *jsonFormatter) Write(entry *zipcode.Entry) error {
+	b, err := json.Marshal(newRecord(entry))
+	if err != nil {
+		return err
+	}
+	sep := ",\n"
+	if f.n == 0 {
+		sep = "[\n"
+	}
+	f.n++
+	if _, err := f.w.WriteString(sep); err != nil {
+		return err
+	}
+	_, err = f.w.Write(b)
+	return err
+}
+
+func (f *jsonFormatter) Close() error {
+	s := "\n]\n"
+	if f.n == 0 {
+		s = "[]\n"
+	}
+	if _, err := f.w.WriteString(s); err != nil {
+		return err
+	}
+	return f.w.Flush()
+}
+
+// jsonlFormatterはエントリを1行に1つずつJSONで出力する。
+type jsonlFormatter struct {
+	w *bufio.Writer
+}
+
+func (f *jsonlFormatter) Write(entry *zipcode.Entry) error {
+	return json.NewEncoder(f.w).Encode(newRecord(entry))
+}
+
+func (f *jsonlFormatter) Close() error {
+	return f.w.Flush()
+}
+
+var csvHeader = []string{
+	"code", "old_zip", "zip",
+	"pref", "region", "town",
+	"pref_ruby", "region_ruby", "town_ruby",
+	"district", "city", "ward",
+	"district_ruby", "city_ruby", "ward_ruby",
+	"is_partial_town", "is_large_town", "is_blocked_scheme", "is_overlapped_zip",
+	"status", "reason", "notice",
+}
+
+// csvFormatterはヘッダ行を持つCSVまたはTSVを出力する。
+type csvFormatter struct {
+	w      *csv.Writer
+	header bool
+}
+
+func newCSVFormatter(w io.Writer, comma rune) *csvFormatter {
+	fout := csv.NewWriter(w)
+	fout.Comma = comma
+	return &csvFormatter{w: fout}
+}
+
+func (f *csvFormatter) Write(entry *zipcode.Entry) error {
+	if !f.header {
+		if err := f.w.Write(csvHeader); err != nil {
+			return err
+		}
+		f.header = true
+	}
+	return f.w.Write([]string{
+		entry.Code, string(entry.OldZip), string(entry.Zip),
+		entry.Pref.Text, entry.Region.Text, entry.Town.Text,
+		entry.Pref.Ruby, entry.Region.Ruby, entry.Town.Ruby,
+		entry.District.Text, entry.City.Text, entry.Ward.Text,
+		entry.District.Ruby, entry.City.Ruby, entry.Ward.Ruby,
+		formatFlag(entry.IsPartialTown),
+		formatFlag(entry.IsLargeTown),
+		formatFlag(entry.IsBlockedScheme),
+		formatFlag(entry.IsOverlappedZip),
+		strconv.Itoa(int(entry.Status)),
+		strconv.Itoa(int(entry.Reason)),
+		entry.Notice,
+	})
+}
+
+func (f *csvFormatter) Close() error {
+	if !f.header {
+		if err := f.w.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+	f.w.Flush()
+	return f.w.Error()
+}
+
+func formatFlag(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFormatter(t *testing.T) {
+	entries := testIndex(t).Lookup("0640941")
+	const (
+		rec = `{"code":"01101","old_zip":"064","zip":"0640941",` +
+			`"pref":{"text":"北海道","ruby":"ﾎｯｶｲﾄﾞｳ"},` +
+			`"region":{"text":"札幌市中央区","ruby":"ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ"},` +
+			`"district":{"text":"","ruby":""},` +
+			`"city":{"text":"札幌市","ruby":"ｻｯﾎﾟﾛｼ"},` +
+			`"ward":{"text":"中央区","ruby":"ﾁｭｳｵｳｸ"},` +
+			`"town":{"text":"旭ケ丘","ruby":"ｱｻﾋｶﾞｵｶ"},` +
+			`"is_partial_town":false,"is_large_town":false,"is_blocked_scheme":true,"is_overlapped_zip":false,` +
+			`"status":0,"reason":0,"notice":""}`
+		header = "code,old_zip,zip,pref,region,town,pref_ruby,region_ruby,town_ruby," +
+			"district,city,ward,district_ruby,city_ruby,ward_ruby," +
+			"is_partial_town,is_large_town,is_blocked_scheme,is_overlapped_zip,status,reason,notice\n"
+		line = "0640941 北海道札幌市中央区旭ケ丘 ﾎｯｶｲﾄﾞｳｻｯﾎﾟﾛｼﾁｭｳｵｳｸｱｻﾋｶﾞｵｶ\n"
+		row  = "01101,064,0640941,北海道,札幌市中央区,旭ケ丘,ﾎｯｶｲﾄﾞｳ,ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ,ｱｻﾋｶﾞｵｶ," +
+			",札幌市,中央区,,ｻｯﾎﾟﾛｼ,ﾁｭｳｵｳｸ,0,0,1,0,0,0,\n"
+	)
+	tsv := func(s string) string {
+		return strings.Replace(s, ",", "\t", -1)
+	}
+	tests := []struct {
+		format string
+		output string
+		empty  string
+	}{
+		{"text", line + line, ""},
+		{"json", "[\n" + rec + ",\n" + rec + "\n]\n", "[]\n"},
+		{"jsonl", rec + "\n" + rec + "\n", ""},
+		{"csv", header + row + row, header},
+		{"tsv", tsv(header + row + row), tsv(header)},
+	}
+	for _, tt := range tests {
+		for _, n := range []int{0, 2} {
+			var buf bytes.Buffer
+			f, err := newFormatter(&buf, tt.format)
+			if err != nil {
+				t.Fatalf("newFormatter(%q) = %v", tt.format, err)
+			}
+			for i := 0; i < n; i++ {
+				if err := f.Write(entries[0]); err != nil {
+					t.Errorf("%s: Write() = %v", tt.format, err)
+				}
+			}
+			if err := f.Close(); err != nil {
+				t.Errorf("%s: Close() = %v", tt.format, err)
+			}
+			expect := tt.empty
+			if n > 0 {
+				expect = tt.output
+			}
+			if s := buf.String(); s != expect {
+				t.Errorf("%s with %d entries = %q; Expect %q", tt.format, n, s, expect)
+			}
+		}
+	}
+	if _, err := newFormatter(&bytes.Buffer{}, "xml"); err == nil {
+		t.Errorf("newFormatter(%q) = nil; Expect an error", "xml")
+	}
+}
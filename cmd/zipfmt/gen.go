@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"lufia.org/pkg/japanese/zipcode"
+)
+
+// genSnapshotFileは生成するパッケージに埋め込むスナップショットのファイル名。
+const genSnapshotFile = "zipdata.snap"
+
+var genTemplate = template.Must(template.New("gen").Parse(`// Code generated by zipfmt gen; DO NOT EDIT.
+
+{{if .File}}//go:generate zipfmt gen -pkg {{.Package}} -o . -f {{printf "%q" .File}}
+
+{{end}}// Package {{.Package}}は郵便番号データを埋め込んだパッケージ。
+package {{.Package}}
+
+import (
+	"bytes"
+	_ "embed"
+	"sync"
+
+	"lufia.org/pkg/japanese/zipcode"
+)
+
+//go:embed {{.Snapshot}}
+var snapshot []byte
+
+var (
+	once  sync.Once
+	index *zipcode.Index
+)
+
+// Indexは埋め込まれた郵便番号データの索引を返す。
+// 初めて呼ばれたときにスナップショットを読み込む。
+func Index() *zipcode.Index {
+	once.Do(func() {
+		entries, err := zipcode.LoadSnapshot(bytes.NewReader(snapshot))
+		if err != nil {
+			panic(err)
+		}
+		index = zipcode.NewIndex(entries)
+	})
+	return index
+}
+
+// Lookupは郵便番号zipのエントリを返す。
+func Lookup(zip string) []*zipcode.Entry {
+	return Index().Lookup(zip)
+}
+
+// Searchは住所addrに該当するエントリを返す。
+func Search(addr string) []*zipcode.Entry {
+	return Index().Search(addr)
+}
+`))
+
+func runGen(args []string) {
+	fs := flag.NewFlagSet("gen", flag.ExitOnError)
+	file := fs.String("f", dataFile(), "read KEN_ALL.CSV or snapshot from `file` instead of stdin; default $ZIPFMT_DATA")
+	pkg := fs.String("pkg", "zipdata", "`name` of the generated package")
+	dir := fs.String("o", ".", "write the package into `dir`")
+	generate := fs.Bool("generate", true, "emit a go:generate line to rebuild the package; requires -f")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s gen [options]\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *generate && *file == "" {
+		log.Fatalln("gen: -f is required to emit a go:generate line; use -generate=false to read stdin")
+	}
+
+	idx, err := loadIndex(*file)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var snap bytes.Buffer
+	if err := zipcode.WriteSnapshot(&snap, idx.Entries()); err != nil {
+		log.Fatalln(err)
+	}
+	var genFile string
+	if *generate {
+		genFile, err = generatePath(*file, *dir)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}
+	b, err := genSource(*pkg, genFile)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	if err := os.MkdirAll(*dir, 0755); err != nil {
+		log.Fatalln(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(*dir, genSnapshotFile), snap.Bytes(), 0644); err != nil {
+		log.Fatalln(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(*dir, *pkg+".go"), b, 0644); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// generatePathは、go generateがdirで実行されたときにfileを指すパスを返す。
+// 相対パスで表せなければ絶対パスを返す。
+func generatePath(file, dir string) (string, error) {
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return "", err
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(absDir, absFile)
+	if err != nil {
+		return filepath.ToSlash(absFile), nil
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// genSourceはパッケージpkgのソースコードを返す。
+// fileが空でなければ、fileから再生成するgo:generate行を含める。
+func genSource(pkg, file string) ([]byte, error) {
+	var src bytes.Buffer
+	err := genTemplate.Execute(&src, map[string]string{
+		"File":     file,
+		"Package":  pkg,
+		"Snapshot": genSnapshotFile,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(src.Bytes())
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenSource(t *testing.T) {
+	tests := []struct {
+		pkg, file string
+		header    string
+	}{
+		{
+			pkg:    "zipdata",
+			file:   "../../KEN_ALL.CSV",
+			header: "// Code generated by zipfmt gen; DO NOT EDIT.\n\n//go:generate zipfmt gen -pkg zipdata -o . -f \"../../KEN_ALL.CSV\"\n\n// Package zipdataは郵便番号データを埋め込んだパッケージ。\npackage zipdata\n",
+		},
+		{
+			pkg:    "postal",
+			header: "// Code generated by zipfmt gen; DO NOT EDIT.\n\n// Package postalは郵便番号データを埋め込んだパッケージ。\npackage postal\n",
+		},
+	}
+	const body = `
+import (
+	"bytes"
+	_ "embed"
+	"sync"
+
+	"lufia.org/pkg/japanese/zipcode"
+)
+
+//go:embed zipdata.snap
+var snapshot []byte
+
+var (
+	once  sync.Once
+	index *zipcode.Index
+)
+
+// Indexは埋め込まれた郵便番号データの索引を返す。
+// 初めて呼ばれたときにスナップショットを読み込む。
+func Index() *zipcode.Index {
+	once.Do(func() {
+		entries, err := zipcode.LoadSnapshot(bytes.NewReader(snapshot))
+		if err != nil {
+			panic(err)
+		}
+		index = zipcode.NewIndex(entries)
+	})
+	return index
+}
+
+// Lookupは郵便番号zipのエントリを返す。
+func Lookup(zip string) []*zipcode.Entry {
+	return Index().Lookup(zip)
+}
+
+// Searchは住所addrに該当するエントリを返す。
+func Search(addr string) []*zipcode.Entry {
+	return Index().Search(addr)
+}
+`
+	for _, tt := range tests {
+		b, err := genSource(tt.pkg, tt.file)
+		if err != nil {
+			t.Errorf("genSource(%q, %q) = %v", tt.pkg, tt.file, err)
+			continue
+		}
+		if s := string(b); s != tt.header+body {
+			t.Errorf("genSource(%q, %q) = %s; Expect %s", tt.pkg, tt.file, s, tt.header+body)
+		}
+	}
+}
+
+func TestGeneratePath(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		file, dir string
+		expect    string
+	}{
+		{"KEN_ALL.CSV", ".", "KEN_ALL.CSV"},
+		{"KEN_ALL.CSV", "internal/zipdata", "../../KEN_ALL.CSV"},
+		{"data/KEN_ALL.CSV", "zipdata", "../data/KEN_ALL.CSV"},
+		{filepath.Join(wd, "KEN_ALL.CSV"), "zipdata", "../KEN_ALL.CSV"},
+	}
+	for _, tt := range tests {
+		s, err := generatePath(tt.file, tt.dir)
+		if err != nil {
+			t.Errorf("generatePath(%q, %q) = %v", tt.file, tt.dir, err)
+			continue
+		}
+		if s != tt.expect {
+			t.Errorf("generatePath(%q, %q) = %q; Expect %q", tt.file, tt.dir, s, tt.expect)
+		}
+	}
+}
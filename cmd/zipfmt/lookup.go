@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"lufia.org/pkg/japanese/zipcode"
+)
+
+// dataFileは郵便番号データの既定のファイル名を返す。
+func dataFile() string {
+	return os.Getenv("ZIPFMT_DATA")
+}
+
+// loadIndexはfileから郵便番号データを読み込む。
+// fileが空文字列なら標準入力から読む。
+func loadIndex(file string) (*zipcode.Index, error) {
+	if file == "" {
+		return readIndex(os.Stdin)
+	}
+	fin, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer fin.Close()
+	idx, err := readIndex(fin)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return idx, nil
+}
+
+// readIndexはrからKEN_ALL.CSVまたはスナップショットを読み込む。
+func readIndex(r io.Reader) (*zipcode.Index, error) {
+	fin := bufio.NewReader(r)
+	b, _ := fin.Peek(4)
+	if !zipcode.IsSnapshot(b) {
+		return zipcode.ReadIndex(fin)
+	}
+	entries, err := zipcode.LoadSnapshot(fin)
+	if err != nil {
+		return nil, err
+	}
+	return zipcode.NewIndex(entries), nil
+}
+
+func runLookup(args []string) {
+	fs := flag.NewFlagSet("lookup", flag.ExitOnError)
+	file := fs.String("f", dataFile(), "read KEN_ALL.CSV or snapshot from `file` instead of stdin; default $ZIPFMT_DATA")
+	addr := fs.Bool("addr", false, "search addresses instead of zip codes")
+	fuzzy := fs.Int("fuzzy", 0, "search addresses allowing typos and variant kanji, printing at most `n` entries")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	idx, err := loadIndex(*file)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	f, err := outputFormatter()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	notFound, err := lookup(f, idx, fs.Args(), *addr, *fuzzy)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	for _, q := range notFound {
+		log.Printf("%s: not found", q)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatalln(err)
+	}
+	if len(notFound) > 0 {
+		os.Exit(1)
+	}
+}
+
+// lookupはqueriesのそれぞれに該当するエントリをfへ出力して、該当しなかったものを返す。
+// fuzzyが正ならあいまい検索で最大fuzzy件、addrなら住所、それ以外は郵便番号で検索する。
+func lookup(f formatter, idx *zipcode.Index, queries []string, addr bool, fuzzy int) ([]string, error) {
+	var notFound []string
+	for _, q := range queries {
+		var a []*zipcode.Entry
+		switch {
+		case fuzzy > 0:
+			for _, m := range idx.FuzzySearch(q, fuzzy) {
+				a = append(a, m.Entry)
+			}
+		case addr:
+			a = idx.Search(q)
+		default:
+			a = idx.Lookup(q)
+		}
+		if len(a) == 0 {
+			notFound = append(notFound, q)
+		}
+		for _, entry := range a {
+			if err := f.Write(entry); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return notFound, nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	idx := testIndex(t)
+	tests := []struct {
+		queries  []string
+		addr     bool
+		fuzzy    int
+		output   string
+		notFound []string
+	}{
+		{
+			queries:  []string{"160-0023", "1000001"},
+			output:   "1600023 東京都新宿区西新宿 ﾄｳｷｮｳﾄｼﾝｼﾞｭｸｸﾆｼｼﾝｼﾞｭｸ\n",
+			notFound: []string{"1000001"},
+		},
+		{
+			queries: []string{"東京都新宿区西新宿2-8-1", "札幌市中央区旭ケ丘"},
+			addr:    true,
+			output: "1600023 東京都新宿区西新宿 ﾄｳｷｮｳﾄｼﾝｼﾞｭｸｸﾆｼｼﾝｼﾞｭｸ\n" +
+				"0640941 北海道札幌市中央区旭ケ丘 ﾎｯｶｲﾄﾞｳｻｯﾎﾟﾛｼﾁｭｳｵｳｸｱｻﾋｶﾞｵｶ\n",
+		},
+		{
+			queries:  []string{"大阪府大阪市"},
+			addr:     true,
+			notFound: []string{"大阪府大阪市"},
+		},
+		{
+			queries: []string{"旭が丘"},
+			fuzzy:   1,
+			output:  "0640941 北海道札幌市中央区旭ケ丘 ﾎｯｶｲﾄﾞｳｻｯﾎﾟﾛｼﾁｭｳｵｳｸｱｻﾋｶﾞｵｶ\n",
+		},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		f, err := newFormatter(&buf, "text")
+		if err != nil {
+			t.Fatalf("newFormatter() = %v", err)
+		}
+		notFound, err := lookup(f, idx, tt.queries, tt.addr, tt.fuzzy)
+		if err != nil {
+			t.Errorf("lookup(%q) = %v", tt.queries, err)
+			continue
+		}
+		f.Close()
+		if s := buf.String(); s != tt.output {
+			t.Errorf("lookup(%q) writes %q; Expect %q", tt.queries, s, tt.output)
+		}
+		if !reflect.DeepEqual(notFound, tt.notFound) {
+			t.Errorf("lookup(%q) = %q; Expect %q", tt.queries, notFound, tt.notFound)
+		}
+	}
+}
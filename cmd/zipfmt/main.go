@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -8,18 +9,76 @@ import (
 	"lufia.org/pkg/japanese/zipcode"
 )
 
+var (
+	flagWarn    = flag.Bool("w", false, "report warnings to stderr")
+	flagLenient = flag.Bool("lenient", false, "repair or skip malformed records")
+	flagFormat  = flag.String("format", "text", "output `format`: text, json, jsonl, csv or tsv")
+	flagTmpl    = flag.String("t", "", "output each entry with Go `template`; overrides -format")
+)
+
+// サブコマンド。引数はサブコマンド名を除いたもの。
+var commands = map[string]func(args []string){
+	"build":  runBuild,
+	"export": runExport,
+	"gen":    runGen,
+	"lookup": runLookup,
+	"serve":  runServe,
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: %s [options] <KEN_ALL.CSV\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "       %s lookup [options] {zip|-addr address}...\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "       %s serve [options] {KEN_ALL.CSV|snapshot}\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "       %s build [options] -o snapshot\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "       %s gen [options]\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "       %s export [options]\n", os.Args[0])
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
 func main() {
 	log.SetFlags(0)
 	log.SetPrefix(os.Args[0] + ": ")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() > 0 {
+		cmd, ok := commands[flag.Arg(0)]
+		if !ok {
+			usage()
+		}
+		cmd(flag.Args()[1:])
+		return
+	}
 
-	var p zipcode.Parser
+	f, err := outputFormatter()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	p := zipcode.Parser{Lenient: *flagLenient}
+	if *flagWarn {
+		p.Warn = func(w *zipcode.Warning) {
+			log.Println(w)
+		}
+	}
 	c := p.Parse(os.Stdin)
 	for v := range c {
-		fmt.Printf("%s %s%s%s %s%s%s\n", v.Zip,
-			v.Pref.Text, v.Region.Text, v.Town.Text,
-			v.Pref.Ruby, v.Region.Ruby, v.Town.Ruby)
+		if err := f.Write(v); err != nil {
+			log.Fatalln(err)
+		}
 	}
 	if p.Error != nil {
 		log.Fatalln(p.Error)
 	}
+	if err := f.Close(); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// outputFormatterは-formatと-tで指定されたformatterを返す。
+func outputFormatter() (formatter, error) {
+	if *flagTmpl != "" {
+		return newTemplateFormatter(os.Stdout, *flagTmpl)
+	}
+	return newFormatter(os.Stdout, *flagFormat)
 }
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"lufia.org/pkg/japanese/zipcode"
+)
+
+// datasetは読み込んだ郵便番号データとその版をあらわす。
+type dataset struct {
+	idx *zipcode.Index
+
+	// データの版。ファイル内容のハッシュ値で、ETagとして使う。
+	version string
+
+	modTime time.Time
+	size    int64
+}
+
+// loadDatasetはfileから郵便番号データを読み込む。
+func loadDataset(file string) (*dataset, error) {
+	fin, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer fin.Close()
+	fi, err := fin.Stat()
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	idx, err := readIndex(io.TeeReader(fin, h))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return &dataset{
+		idx:     idx,
+		version: hex.EncodeToString(h.Sum(nil)[:8]),
+		modTime: fi.ModTime(),
+		size:    fi.Size(),
+	}, nil
+}
+
+// serverは郵便番号データをJSONで返すHTTPサーバ。
+type server struct {
+	file string
+
+	mu   sync.RWMutex
+	data *dataset
+}
+
+func (s *server) dataset() *dataset {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.data
+}
+
+// watchはintervalごとにファイルの更新を確認して、更新されていれば読み込み直す。
+// 読み込みに失敗した場合は、それまでのデータを使い続ける。
+func (s *server) watch(interval time.Duration) {
+	for range time.Tick(interval) {
+		fi, err := os.Stat(s.file)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		data := s.dataset()
+		if fi.ModTime().Equal(data.modTime) && fi.Size() == data.size {
+			continue
+		}
+		data, err = loadDataset(s.file)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		s.mu.Lock()
+		s.data = data
+		s.mu.Unlock()
+		log.Printf("%s: reloaded version %s", s.file, data.version)
+	}
+}
+
+func (s *server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zip/", s.handleZip)
+	mux.HandleFunc("/search", s.handleSearch)
+	mux.HandleFunc("/prefectures", s.handlePrefectures)
+	mux.HandleFunc("/prefectures/", s.handleCities)
+	mux.HandleFunc("/api/search", s.handleZipcloud)
+	return mux
+}
+
+// handleZipは/zip/{code}に該当するエントリを返す。
+func (s *server) handleZip(w http.ResponseWriter, r *http.Request) {
+	data := s.dataset()
+	code := strings.TrimPrefix(r.URL.Path, "/zip/")
+	a := data.idx.Lookup(code)
+	if len(a) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	writeEntries(w, r, data, a)
+}
+
+// handleSearchは/search?q={address}に該当するエントリを返す。
+func (s *server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	data := s.dataset()
+	q := r.FormValue("q")
+	if q == "" {
+		http.Error(w, "missing q parameter", http.StatusBadRequest)
+		return
+	}
+	writeEntries(w, r, data, data.idx.Search(q))
+}
+
+// areaは都道府県または市区町村をあらわす。
+type area struct {
+	Code string `json:"code"`
+	Name name   `json:"name"`
+}
+
+// handlePrefecturesはすべての都道府県を返す。
+func (s *server) handlePrefectures(w http.ResponseWriter, r *http.Request) {
+	data := s.dataset()
+	a := collectAreas(data.idx.Entries(), func(entry *zipcode.Entry) (string, zipcode.Name) {
+		return entry.Code[:2], entry.Pref
+	})
+	writeJSON(w, r, data, a)
+}
+
+// cityは市区町村をあらわす。
+type city struct {
+	Code     string `json:"code"`
+	FullCode string `json:"full_code"`
+	Name     name   `json:"name"`
+	Kind     string `json:"kind"`
+}
+
+// handleCitiesは/prefectures/{code}/citiesで都道府県に属する市区町村を返す。
+func (s *server) handleCities(w http.ResponseWriter, r *http.Request) {
+	data := s.dataset()
+	a := strings.Split(strings.TrimPrefix(r.URL.Path, "/prefectures/"), "/")
+	if len(a) != 2 || a[1] != "cities" {
+		http.NotFound(w, r)
+		return
+	}
+	if _, err := strconv.Atoi(a[0]); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	var cities []*city
+	for _, m := range data.idx.Municipalities(a[0]) {
+		cities = append(cities, &city{
+			Code:     m.Code,
+			FullCode: m.FullCode(),
+			Name:     name(m.Name),
+			Kind:     m.Kind.String(),
+		})
+	}
+	if len(cities) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, r, data, cities)
+}
+
+// collectAreasはfでエントリから取り出したコードと名前の組を重複なく集めて、コード順に返す。
+func collectAreas(entries []*zipcode.Entry, f func(entry *zipcode.Entry) (string, zipcode.Name)) []*area {
+	m := make(map[string]*area)
+	var a []*area
+	for _, entry := range entries {
+		code, n := f(entry)
+		if _, ok := m[code]; ok {
+			continue
+		}
+		m[code] = &area{Code: code, Name: name(n)}
+		a = append(a, m[code])
+	}
+	sort.Slice(a, func(i, j int) bool {
+		return a[i].Code < a[j].Code
+	})
+	return a
+}
+
+func writeEntries(w http.ResponseWriter, r *http.Request, data *dataset, entries []*zipcode.Entry) {
+	a := make([]*record, len(entries))
+	for i, entry := range entries {
+		a[i] = newRecord(entry)
+	}
+	writeJSON(w, r, data, a)
+}
+
+// writeJSONはvをJSONで返す。
+// 同じ版のデータに対する条件付きリクエストにはNot Modifiedを返す。
+func writeJSON(w http.ResponseWriter, r *http.Request, data *dataset, v interface{}) {
+	etag := `"` + data.version + `"`
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Println(err)
+	}
+}
+
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "listen `address`")
+	interval := fs.Duration("interval", 10*time.Second, "check the data file for changes every `duration`")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s serve [options] {KEN_ALL.CSV|snapshot}\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	s := &server{file: fs.Arg(0)}
+	data, err := loadDataset(s.file)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	s.data = data
+	go s.watch(*interval)
+	log.Fatalln(http.ListenAndServe(*addr, s.Handler()))
+}
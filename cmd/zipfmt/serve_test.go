@@ -0,0 +1,91 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"lufia.org/pkg/japanese/zipcode"
+)
+
+var testData = []string{
+	`13104,"160  ","1600023","ﾄｳｷｮｳﾄ","ｼﾝｼﾞｭｸｸ","ﾆｼｼﾝｼﾞｭｸ(ﾂｷﾞﾉﾋﾞﾙｦﾉｿﾞｸ)","東京都","新宿区","西新宿（次のビルを除く）",0,0,1,0,0,0`,
+	`01101,"064  ","0640941","ﾎｯｶｲﾄﾞｳ","ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ","ｱｻﾋｶﾞｵｶ","北海道","札幌市中央区","旭ケ丘",0,0,1,0,0,0`,
+}
+
+func testIndex(t *testing.T) *zipcode.Index {
+	t.Helper()
+	idx, err := readIndex(strings.NewReader(strings.Join(testData, "\n")))
+	if err != nil {
+		t.Fatalf("readIndex() = %v", err)
+	}
+	return idx
+}
+
+func testServer(t *testing.T) *server {
+	t.Helper()
+	return &server{data: &dataset{idx: testIndex(t), version: "0123456789abcdef"}}
+}
+
+func TestServeETag(t *testing.T) {
+	h := testServer(t).Handler()
+	tests := []struct {
+		path        string
+		ifNoneMatch string
+		status      int
+	}{
+		{"/zip/1600023", "", http.StatusOK},
+		{"/zip/1600023", `"0123456789abcdef"`, http.StatusNotModified},
+		{"/zip/1600023", `"fedcba9876543210"`, http.StatusOK},
+		{"/prefectures", `"0123456789abcdef"`, http.StatusNotModified},
+		{"/zip/1000001", "", http.StatusNotFound},
+		{"/prefectures/99/cities", "", http.StatusNotFound},
+		{"/prefectures/13/towns", "", http.StatusNotFound},
+		{"/search", "", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", tt.path, nil)
+		if tt.ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", tt.ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != tt.status {
+			t.Errorf("GET %s (If-None-Match: %s) = %d; Expect %d", tt.path, tt.ifNoneMatch, w.Code, tt.status)
+		}
+		if tt.status == http.StatusOK || tt.status == http.StatusNotModified {
+			if etag := w.Header().Get("ETag"); etag != `"0123456789abcdef"` {
+				t.Errorf("GET %s: ETag = %s; Expect %s", tt.path, etag, `"0123456789abcdef"`)
+			}
+		}
+		if tt.status == http.StatusNotModified && w.Body.Len() != 0 {
+			t.Errorf("GET %s: body = %q; Expect empty", tt.path, w.Body.String())
+		}
+	}
+}
+
+func TestServeZip(t *testing.T) {
+	h := testServer(t).Handler()
+	r := httptest.NewRequest("GET", "/zip/064-0941", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /zip/064-0941 = %d; Expect %d", w.Code, http.StatusOK)
+	}
+	if s := w.Header().Get("Content-Type"); s != "application/json; charset=utf-8" {
+		t.Errorf("Content-Type = %s; Expect application/json", s)
+	}
+	expect := `[{"code":"01101","old_zip":"064","zip":"0640941",` +
+		`"pref":{"text":"北海道","ruby":"ﾎｯｶｲﾄﾞｳ"},` +
+		`"region":{"text":"札幌市中央区","ruby":"ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ"},` +
+		`"district":{"text":"","ruby":""},` +
+		`"city":{"text":"札幌市","ruby":"ｻｯﾎﾟﾛｼ"},` +
+		`"ward":{"text":"中央区","ruby":"ﾁｭｳｵｳｸ"},` +
+		`"town":{"text":"旭ケ丘","ruby":"ｱｻﾋｶﾞｵｶ"},` +
+		`"is_partial_town":false,"is_large_town":false,"is_blocked_scheme":true,"is_overlapped_zip":false,` +
+		`"status":0,"reason":0,"notice":""}]` + "\n"
+	if s := w.Body.String(); s != expect {
+		t.Errorf("GET /zip/064-0941 = %s; Expect %s", s, expect)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"text/template"
+
+	"lufia.org/pkg/japanese/zipcode"
+)
+
+var templateFuncs = template.FuncMap{
+	// zipは"1500001"を"150-0001"の形式にする。
+	"zip": func(z zipcode.Zip) string {
+		return z.Hyphenated()
+	},
+	"katakana": func(name zipcode.Name) string {
+		return name.Katakana()
+	},
+	"hiragana": func(name zipcode.Name) string {
+		return name.Hiragana()
+	},
+	// addressは都道府県名から町域名までを連結する。
+	"address": func(entry *zipcode.Entry) string {
+		return entry.Address()
+	},
+	// addressRubyはaddressのカナ表記を返す。
+	"addressRuby": func(entry *zipcode.Entry) string {
+		return entry.Pref.Ruby + entry.Region.Ruby + entry.Town.Ruby
+	},
+	// romajiは"Nishishinjuku, Shinjuku-ku, Tokyo"の形式で長音を省いたローマ字の住所を返す。
+	"romaji": func(entry *zipcode.Entry) string {
+		return entry.RomajiAddress(zipcode.LongVowelOmit)
+	},
+}
+
+// unescaperはコマンドラインで渡しにくい文字を解釈する。
+var unescaper = strings.NewReplacer(`\t`, "\t", `\n`, "\n", `\\`, `\`)
+
+// unescapeTextはテンプレートsのアクションの外側だけをunescaperで解釈する。
+// {{printf "%s\n" .Zip}}のようなアクションの中の文字列はテンプレートの構文に任せる。
+func unescapeText(s string) string {
+	var buf strings.Builder
+	for {
+		i := strings.Index(s, "{{")
+		if i < 0 {
+			buf.WriteString(unescaper.Replace(s))
+			return buf.String()
+		}
+		buf.WriteString(unescaper.Replace(s[:i]))
+		s = s[i:]
+		j := strings.Index(s, "}}")
+		if j < 0 {
+			buf.WriteString(s)
+			return buf.String()
+		}
+		buf.WriteString(s[:j+2])
+		s = s[j+2:]
+	}
+}
+
+// templateFormatterはエントリごとにテンプレートを実行して、結果を1行ずつ出力する。
+type templateFormatter struct {
+	w *bufio.Writer
+	t *template.Template
+}
+
+func newTemplateFormatter(w io.Writer, s string) (*templateFormatter, error) {
+	t, err := template.New("zipfmt").Funcs(templateFuncs).Parse(unescapeText(s))
+	if err != nil {
+		return nil, err
+	}
+	return &templateFormatter{w: bufio.NewWriter(w), t: t}, nil
+}
+
+func (f *templateFormatter) Write(entry *zipcode.Entry) error {
+	if err := f.t.Execute(f.w, entry); err != nil {
+		return err
+	}
+	return f.w.WriteByte('\n')
+}
+
+func (f *templateFormatter) Close() error {
+	return f.w.Flush()
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"lufia.org/pkg/japanese/zipcode"
+)
+
+func TestTemplateFormatter(t *testing.T) {
+	entry := &zipcode.Entry{
+		Zip:  "1600023",
+		Pref: zipcode.Name{Text: "東京都", Ruby: "ﾄｳｷｮｳﾄ"},
+	}
+	tests := []struct {
+		tmpl   string
+		expect string
+	}{
+		{`{{zip .Zip}}\t{{.Pref.Text}}`, "160-0023\t東京都\n"},
+		{`{{printf "%s\n" .Zip}}`, "1600023\n\n"},
+		{`{{printf "%s\\t" .Zip}}\\`, "1600023\\t\\\n"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		f, err := newTemplateFormatter(&buf, tt.tmpl)
+		if err != nil {
+			t.Errorf("newTemplateFormatter(%q) = %v", tt.tmpl, err)
+			continue
+		}
+		if err := f.Write(entry); err != nil {
+			t.Errorf("Write(%q) = %v", tt.tmpl, err)
+			continue
+		}
+		f.Close()
+		if s := buf.String(); s != tt.expect {
+			t.Errorf("template %q = %q; Expect %q", tt.tmpl, s, tt.expect)
+		}
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	entry := testIndex(t).Lookup("0640941")[0]
+	tests := []struct {
+		tmpl   string
+		expect string
+	}{
+		{`{{zip .Zip}}`, "064-0941"},
+		{`{{katakana .Town}}`, "アサヒガオカ"},
+		{`{{hiragana .Town}}`, "あさひがおか"},
+		{`{{address .}}`, "北海道札幌市中央区旭ケ丘"},
+		{`{{addressRuby .}}`, "ﾎｯｶｲﾄﾞｳｻｯﾎﾟﾛｼﾁｭｳｵｳｸｱｻﾋｶﾞｵｶ"},
+		{`{{romaji .}}`, "Asahigaoka, Chuo-ku, Sapporo-shi, Hokkaido"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		f, err := newTemplateFormatter(&buf, tt.tmpl)
+		if err != nil {
+			t.Errorf("newTemplateFormatter(%q) = %v", tt.tmpl, err)
+			continue
+		}
+		if err := f.Write(entry); err != nil {
+			t.Errorf("Write(%q) = %v", tt.tmpl, err)
+			continue
+		}
+		f.Close()
+		if s := buf.String(); s != tt.expect+"\n" {
+			t.Errorf("template %q = %q; Expect %q", tt.tmpl, s, tt.expect+"\n")
+		}
+	}
+}
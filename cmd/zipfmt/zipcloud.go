@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"lufia.org/pkg/japanese/zipcode"
+)
+
+// zipcloudResponseはzipcloudの郵便番号検索APIと同じ形式の応答。
+type zipcloudResponse struct {
+	Message *string           `json:"message"`
+	Results []*zipcloudResult `json:"results"`
+	Status  int               `json:"status"`
+}
+
+type zipcloudResult struct {
+	Address1 string `json:"address1"`
+	Address2 string `json:"address2"`
+	Address3 string `json:"address3"`
+	Kana1    string `json:"kana1"`
+	Kana2    string `json:"kana2"`
+	Kana3    string `json:"kana3"`
+	Prefcode string `json:"prefcode"`
+	Zipcode  string `json:"zipcode"`
+}
+
+// zipcloudの既定の最大件数。
+const zipcloudLimit = 20
+
+var (
+	zipcloudZipRegexp      = regexp.MustCompile(`^[0-9]{7}$`)
+	zipcloudCallbackRegexp = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$.]*$`)
+)
+
+// handleZipcloudは/api/search?zipcode={zip}をzipcloudと同じ形式で返す。
+// callbackパラメータがあればJSONPで返す。
+func (s *server) handleZipcloud(w http.ResponseWriter, r *http.Request) {
+	callback := r.FormValue("callback")
+	if callback != "" && !zipcloudCallbackRegexp.MatchString(callback) {
+		http.Error(w, "invalid callback", http.StatusBadRequest)
+		return
+	}
+	resp := s.zipcloudSearch(r.FormValue("zipcode"), r.FormValue("limit"))
+	b, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if callback != "" {
+		w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+		b = []byte(callback + "(" + string(b) + ")")
+	} else {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	}
+	if _, err := w.Write(b); err != nil {
+		log.Println(err)
+	}
+}
+
+func (s *server) zipcloudSearch(zip, limit string) *zipcloudResponse {
+	if zip == "" {
+		return zipcloudError("必須パラメータが指定されていません。")
+	}
+	zip = strings.Replace(zip, "-", "", -1)
+	if !zipcloudZipRegexp.MatchString(zip) {
+		for _, c := range zip {
+			if c < '0' || c > '9' {
+				return zipcloudError("パラメータ「郵便番号」に不正な文字が含まれています。")
+			}
+		}
+		return zipcloudError("パラメータ「郵便番号」の桁数が不正です。")
+	}
+	n := zipcloudLimit
+	if limit != "" {
+		v, err := strconv.Atoi(limit)
+		if err != nil || v < 1 {
+			return zipcloudError("パラメータ「最大件数」が不正です。")
+		}
+		n = v
+	}
+
+	resp := &zipcloudResponse{Status: http.StatusOK}
+	for _, entry := range s.dataset().idx.Lookup(zip) {
+		if len(resp.Results) >= n {
+			break
+		}
+		resp.Results = append(resp.Results, newZipcloudResult(entry))
+	}
+	return resp
+}
+
+func zipcloudError(msg string) *zipcloudResponse {
+	return &zipcloudResponse{
+		Message: &msg,
+		Status:  http.StatusBadRequest,
+	}
+}
+
+func newZipcloudResult(entry *zipcode.Entry) *zipcloudResult {
+	// zipcloudの都道府県コードは先頭の0を持たない。
+	prefcode := strings.TrimPrefix(entry.Code[:2], "0")
+	return &zipcloudResult{
+		Address1: entry.Pref.Text,
+		Address2: entry.Region.Text,
+		Address3: entry.Town.Text,
+		Kana1:    entry.Pref.Ruby,
+		Kana2:    entry.Region.Ruby,
+		Kana3:    entry.Town.Ruby,
+		Prefcode: prefcode,
+		Zipcode:  string(entry.Zip),
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestZipcloud(t *testing.T) {
+	h := testServer(t).Handler()
+	result := `{"address1":"北海道","address2":"札幌市中央区","address3":"旭ケ丘",` +
+		`"kana1":"ﾎｯｶｲﾄﾞｳ","kana2":"ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ","kana3":"ｱｻﾋｶﾞｵｶ",` +
+		`"prefcode":"1","zipcode":"0640941"}`
+	tests := []struct {
+		query       string
+		contentType string
+		body        string
+	}{
+		{
+			query:       "zipcode=0640941",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"message":null,"results":[` + result + `],"status":200}`,
+		},
+		{
+			query:       "zipcode=064-0941&callback=cb",
+			contentType: "text/javascript; charset=utf-8",
+			body:        `cb({"message":null,"results":[` + result + `],"status":200})`,
+		},
+		{
+			query:       "zipcode=1000001",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"message":null,"results":null,"status":200}`,
+		},
+		{
+			query:       "",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"message":"必須パラメータが指定されていません。","results":null,"status":400}`,
+		},
+		{
+			query:       "zipcode=064094",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"message":"パラメータ「郵便番号」の桁数が不正です。","results":null,"status":400}`,
+		},
+		{
+			query:       "zipcode=064094a",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"message":"パラメータ「郵便番号」に不正な文字が含まれています。","results":null,"status":400}`,
+		},
+		{
+			query:       "zipcode=0640941&limit=0",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"message":"パラメータ「最大件数」が不正です。","results":null,"status":400}`,
+		},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/api/search?"+tt.query, nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		// zipcloudはエラーでもHTTPのステータスは200で、本文のstatusで区別する。
+		if w.Code != http.StatusOK {
+			t.Errorf("GET /api/search?%s = %d; Expect %d", tt.query, w.Code, http.StatusOK)
+		}
+		if s := w.Header().Get("Content-Type"); s != tt.contentType {
+			t.Errorf("GET /api/search?%s: Content-Type = %s; Expect %s", tt.query, s, tt.contentType)
+		}
+		if s := w.Body.String(); s != tt.body {
+			t.Errorf("GET /api/search?%s = %s; Expect %s", tt.query, s, tt.body)
+		}
+	}
+
+	r := httptest.NewRequest("GET", "/api/search?zipcode=0640941&callback=alert(1)", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("GET /api/search with invalid callback = %d; Expect %d", w.Code, http.StatusBadRequest)
+	}
+}
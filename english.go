@@ -0,0 +1,100 @@
+package zipcode
+
+import (
+	"strings"
+)
+
+// Streetは町域より後の住所を表す。
+type Street struct {
+	// 丁目、番地、号。それぞれ算用数字で、なければ空。
+	Chome  string
+	Banchi string
+	Go     string
+
+	// 建物名や部屋番号。英語で書かれたものをそのまま使う。
+	Building string
+}
+
+// Stringは"2丁目8番1号"の形式で丁目、番地、号を返す。建物名は含まない。
+func (street Street) String() string {
+	var s string
+	if street.Chome != "" {
+		s += street.Chome + "丁目"
+	}
+	switch {
+	case street.Banchi != "" && street.Go != "":
+		s += street.Banchi + "番" + street.Go + "号"
+	case street.Banchi != "":
+		s += street.Banchi + "番地"
+	}
+	return s
+}
+
+// numberは"2-8-1"のように丁目、番地、号をハイフンで連結する。
+func (street Street) number() string {
+	var a []string
+	for _, s := range []string{street.Chome, street.Banchi, street.Go} {
+		if s != "" {
+			a = append(a, s)
+		}
+	}
+	return strings.Join(a, "-")
+}
+
+// EnglishOptionsは英語の住所の書き方を指定する。
+type EnglishOptions struct {
+	// 長音の書き方。
+	LongVowel LongVowel
+
+	// trueならすべて大文字で書く。
+	Upper bool
+
+	// trueなら"Shinjuku-ku"の"-ku"のような行政区画の名前を省く。
+	OmitSuffix bool
+}
+
+// EnglishAddressは郵便物の宛先として使える英語の住所を、
+//
+//	Building
+//	2-8-1 Nishishinjuku
+//	Shinjuku-ku
+//	Tokyo 160-0023
+//	Japan
+//
+// のように建物名から国名まで1行ずつ返す。streetの空のフィールドは省く。
+func (entry *Entry) EnglishAddress(street Street, opts EnglishOptions) string {
+	var lines []string
+	if street.Building != "" {
+		lines = append(lines, street.Building)
+	}
+	var line []string
+	if s := street.number(); s != "" {
+		line = append(line, s)
+	}
+	if entry.Town.Ruby != "" {
+		line = append(line, entry.Town.Romaji(opts.LongVowel))
+	}
+	if len(line) > 0 {
+		lines = append(lines, strings.Join(line, " "))
+	}
+	if a := entry.romajiRegions(opts.LongVowel, !opts.OmitSuffix); len(a) > 0 {
+		lines = append(lines, strings.Join(a, ", "))
+	}
+	line = line[:0]
+	if entry.Pref.Ruby != "" {
+		line = append(line, romajiPref(entry.Pref, opts.LongVowel))
+	}
+	if entry.Zip != "" {
+		line = append(line, entry.HyphenatedZip())
+	}
+	if len(line) > 0 {
+		lines = append(lines, strings.Join(line, " "))
+	}
+	lines = append(lines, "Japan")
+
+	s := strings.Join(lines, "\n")
+	if opts.Upper {
+		s = strings.ToUpper(s)
+	}
+	return s
+}
@@ -0,0 +1,50 @@
+package zipcode
+
+import (
+	"testing"
+)
+
+func TestEnglishAddress(t *testing.T) {
+	idx := testIndex(t)
+	addr, err := idx.ParseAddress("東京都新宿区西新宿2-8-1 Tokyo Metropolitan Government Building")
+	if err != nil {
+		t.Fatalf("ParseAddress() = %v", err)
+	}
+	tests := []struct {
+		zip    string
+		street Street
+		opts   EnglishOptions
+		expect string
+	}{
+		{
+			zip:    "1600023",
+			street: addr.Street(),
+			expect: "Tokyo Metropolitan Government Building\n2-8-1 Nishishinjuku\nShinjuku-ku\nTokyo 160-0023\nJapan",
+		},
+		{
+			zip:    "0640941",
+			street: Street{Chome: "3", Banchi: "15"},
+			opts:   EnglishOptions{Upper: true, OmitSuffix: true},
+			expect: "3-15 ASAHIGAOKA\nCHUO, SAPPORO\nHOKKAIDO 064-0941\nJAPAN",
+		},
+		{
+			zip:    "0640941",
+			opts:   EnglishOptions{LongVowel: LongVowelMacron},
+			expect: "Asahigaoka\nChūō-ku, Sapporo-shi\nHokkaidō 064-0941\nJapan",
+		},
+		{
+			zip:    "1600000",
+			street: Street{Banchi: "1", Go: "2"},
+			expect: "1-2\nShinjuku-ku\nTokyo 160-0000\nJapan",
+		},
+	}
+	for _, tt := range tests {
+		a := idx.Lookup(tt.zip)
+		if len(a) != 1 {
+			t.Fatalf("Lookup(%q) = %v", tt.zip, a)
+		}
+		if s := a[0].EnglishAddress(tt.street, tt.opts); s != tt.expect {
+			t.Errorf("EnglishAddress(%v, %v) = %q; Expect %q", tt.street, tt.opts, s, tt.expect)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package zipcode
+
+import (
+	"sort"
+	"unicode"
+)
+
+// itaijiは住所で使われる異体字と、検索のために寄せる字体。
+var itaiji = map[rune]rune{
+	'澤': '沢', '邊': '辺', '邉': '辺', '濱': '浜', '嶋': '島', '嶌': '島',
+	'齋': '斉', '齊': '斉', '斎': '斉', '髙': '高', '﨑': '崎', '嵜': '崎',
+	'國': '国', '瀧': '滝', '藪': '薮', '舘': '館', '冨': '富', '德': '徳',
+	'惠': '恵', '眞': '真', '萬': '万', '廣': '広', '櫻': '桜', '檜': '桧',
+	'鷗': '鴎', '驒': '騨', '條': '条', '槇': '槙', '埜': '野', '龍': '竜',
+	'曾': '曽', '塚': '塚', '淺': '浅', '會': '会', '壽': '寿', '與': '与',
+}
+
+// smallKanaは小書きのカタカナと、対応する大きいカタカナ。
+var smallKana = map[rune]rune{
+	'ァ': 'ア', 'ィ': 'イ', 'ゥ': 'ウ', 'ェ': 'エ', 'ォ': 'オ',
+	'ッ': 'ツ', 'ャ': 'ヤ', 'ュ': 'ユ', 'ョ': 'ヨ', 'ヮ': 'ワ',
+	'ヵ': 'カ', 'ヶ': 'ケ',
+}
+
+// foldVariantsは検索のためにsの異体字、ひらがな、小書きのカナなどを1つの字体に寄せる。
+// "旭ヶ丘"、"旭ケ丘"、"旭が丘"、"旭ガ丘"はすべて"旭ケ丘"になる。
+// "ガ"を"ケ"に寄せるのは、"ヶ"と同じように漢字の後にあって、漢字が続くか末尾にある場合だけ。
+// 末尾を含めるのは、"旭が"のような補完の途中の入力も寄せるため。
+func foldVariants(s string) string {
+	r := []rune(toKatakana(normalizeAddress(s)))
+	for i, c := range r {
+		if c >= 'ぁ' && c <= 'ゖ' {
+			c = c - 'ぁ' + 'ァ'
+		}
+		if c1, ok := itaiji[c]; ok {
+			c = c1
+		}
+		if c1, ok := smallKana[c]; ok {
+			c = c1
+		}
+		if c == 'ガ' && i > 0 && unicode.Is(unicode.Han, r[i-1]) && (i+1 == len(r) || unicode.Is(unicode.Han, r[i+1])) {
+			c = 'ケ'
+		}
+		r[i] = c
+	}
+	return string(r)
+}
+
+// foldedEntryはあいまい検索のために、エントリの住所とカナ表記をfoldVariantsで寄せたもの。
+type foldedEntry struct {
+	text []rune
+	ruby []rune
+}
+
+// foldedEntriesはidx.entriesと同じ順にfoldedEntryを返す。初めて呼ばれたときに作成する。
+func (idx *Index) foldedEntries() []foldedEntry {
+	idx.foldOnce.Do(func() {
+		idx.folded = make([]foldedEntry, len(idx.entries))
+		for i, entry := range idx.entries {
+			idx.folded[i] = foldedEntry{
+				text: []rune(foldVariants(entry.Pref.Text + entry.Region.Text + entry.Town.Text)),
+				ruby: []rune(foldVariants(entry.Pref.Ruby + entry.Region.Ruby + entry.Town.Ruby)),
+			}
+		}
+	})
+	return idx.folded
+}
+
+// Matchはあいまい検索で見つかったエントリ。
+type Match struct {
+	Entry *Entry
+
+	// 検索語と住所の編集距離。0なら異体字などを除いて完全に一致している。
+	Distance int
+}
+
+// FuzzySearchは住所または住所のカナ表記にqを含むエントリを、近いものから最大n件返す。
+// nが0以下なら件数を制限しない。
+//
+// 異体字、ひらがなとカタカナ、小書きのカナ、"旭ヶ丘"と"旭ケ丘"と"旭ガ丘"の違いは同じ文字とみなす。
+// 誤字は検索語の長さの3分の1文字まで許す。
+// 編集距離が同じものは郵便番号の順に並べる。
+func (idx *Index) FuzzySearch(q string, n int) []*Match {
+	p := []rune(foldVariants(q))
+	if len(p) == 0 {
+		return nil
+	}
+	max := len(p) / 3
+	folded := idx.foldedEntries()
+	var a []*Match
+	for i, entry := range idx.entries {
+		f := &folded[i]
+		d := substringDistance(p, f.text)
+		if d > 0 {
+			if d1 := substringDistance(p, f.ruby); d1 < d {
+				d = d1
+			}
+		}
+		if d <= max {
+			a = append(a, &Match{Entry: entry, Distance: d})
+		}
+	}
+	sort.SliceStable(a, func(i, j int) bool {
+		if a[i].Distance != a[j].Distance {
+			return a[i].Distance < a[j].Distance
+		}
+		return a[i].Entry.Zip < a[j].Entry.Zip
+	})
+	if n > 0 && len(a) > n {
+		a = a[:n]
+	}
+	return a
+}
+
+// substringDistanceはtのすべての部分文字列のうち、pとの編集距離の最小値を返す。
+func substringDistance(p, t []rune) int {
+	// d[i]はpの先頭i文字と、tのj文字目で終わる部分文字列との編集距離。
+	d := make([]int, len(p)+1)
+	for i := range d {
+		d[i] = i
+	}
+	min := d[len(p)]
+	for j := 1; j <= len(t); j++ {
+		prev := d[0] // d[i-1]の1つ前の列の値
+		for i := 1; i <= len(p); i++ {
+			cost := 1
+			if p[i-1] == t[j-1] {
+				cost = 0
+			}
+			v := prev + cost
+			if d[i]+1 < v {
+				v = d[i] + 1
+			}
+			if d[i-1]+1 < v {
+				v = d[i-1] + 1
+			}
+			prev, d[i] = d[i], v
+		}
+		if d[len(p)] < min {
+			min = d[len(p)]
+		}
+	}
+	return min
+}
@@ -0,0 +1,74 @@
+package zipcode
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFuzzySearch(t *testing.T) {
+	data := append([]string{
+		`16207,"93801","9380174","ﾄﾔﾏｹﾝ","ｸﾛﾍﾞｼ","ｳﾅﾂﾞｷﾏﾁｵﾄｻﾞﾜ","富山県","黒部市","宇奈月町音澤",1,0,0,0,0,0`,
+	}, testIndexData...)
+	idx, err := ReadIndex(strings.NewReader(strings.Join(data, "\n")))
+	if err != nil {
+		t.Fatalf("ReadIndex() = %v", err)
+	}
+	tests := []struct {
+		q        string
+		zip      Zip
+		distance int
+	}{
+		{"宇奈月町音沢", "9380174", 0},
+		{"うなづきまちおとざわ", "9380174", 0},
+		{"宇奈月町音択", "9380174", 1},
+		{"旭が丘", "0640941", 0},
+		{"旭ヶ丘", "0640941", 0},
+		{"西新宿", "1600023", 0},
+	}
+	for _, tt := range tests {
+		a := idx.FuzzySearch(tt.q, 1)
+		if len(a) != 1 || a[0].Entry.Zip != tt.zip || a[0].Distance != tt.distance {
+			t.Errorf("FuzzySearch(%q) = %v; Expect %s (%d)", tt.q, a, tt.zip, tt.distance)
+		}
+	}
+	if a := idx.FuzzySearch("大阪府大阪市", 0); len(a) != 0 {
+		t.Errorf("FuzzySearch(%q) = %v; Expect empty", "大阪府大阪市", a)
+	}
+}
+
+func TestFoldVariants(t *testing.T) {
+	tests := []struct {
+		s, want string
+	}{
+		{"旭ヶ丘", "旭ケ丘"},
+		{"旭が丘", "旭ケ丘"},
+		{"旭ガ丘", "旭ケ丘"},
+		{"霞ヶ関", "霞ケ関"},
+		{"旭が", "旭ケ"},
+		{"ガス", "ガス"},
+		{"ビヤガイチ", "ビヤガイチ"},
+		{"あさひがおか", "アサヒガオカ"},
+	}
+	for _, tt := range tests {
+		if s := foldVariants(tt.s); s != tt.want {
+			t.Errorf("foldVariants(%q) = %q; Expect %q", tt.s, s, tt.want)
+		}
+	}
+}
+
+func TestSubstringDistance(t *testing.T) {
+	tests := []struct {
+		p, s string
+		d    int
+	}{
+		{"音沢", "宇奈月町音沢", 0},
+		{"音択", "宇奈月町音沢", 1},
+		{"宇奈月音沢", "宇奈月町音沢", 1},
+		{"abc", "", 3},
+	}
+	for _, tt := range tests {
+		if d := substringDistance([]rune(tt.p), []rune(tt.s)); d != tt.d {
+			t.Errorf("substringDistance(%q, %q) = %d; Expect %d", tt.p, tt.s, d, tt.d)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package zipcode
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Indexは郵便番号データを検索するための索引。
+type Index struct {
+	entries []*Entry
+	byZip   map[Zip][]*Entry
+	cities  *municipalityTable
+
+	gazOnce sync.Once
+	gaz     *gazetteer
+
+	foldOnce sync.Once
+	folded   []foldedEntry
+}
+
+// NewIndexはentriesを検索するIndexを返す。
+func NewIndex(entries []*Entry) *Index {
+	idx := &Index{
+		entries: entries,
+		byZip:   make(map[Zip][]*Entry),
+	}
+	for _, entry := range entries {
+		idx.byZip[entry.Zip] = append(idx.byZip[entry.Zip], entry)
+	}
+	idx.cities = newMunicipalityTable(entries)
+	return idx
+}
+
+// ReadIndexはrからKEN_ALL.CSVを読み込んでIndexを返す。
+func ReadIndex(r io.Reader) (*Index, error) {
+	var (
+		parser  Parser
+		entries []*Entry
+	)
+	for entry := range parser.Parse(r) {
+		entries = append(entries, entry)
+	}
+	if parser.Error != nil {
+		return nil, parser.Error
+	}
+	return NewIndex(entries), nil
+}
+
+// Entriesは索引に含まれるすべてのエントリを返す。
+func (idx *Index) Entries() []*Entry {
+	return idx.entries
+}
+
+// Lookupは郵便番号zipのエントリを返す。
+// zipは"1600023"の他に"160-0023"や"〒１６０−００２３"などの書式も受け付ける。
+func (idx *Index) Lookup(zip string) []*Entry {
+	z, err := ParseZip(zip)
+	if err != nil {
+		return nil
+	}
+	return idx.byZip[z]
+}
+
+// Searchは住所addrに該当するエントリを返す。
+//
+// 住所がエントリの住所で始まる場合は、最も長く一致したエントリを返す。
+// そうでなければ、addrを住所の一部に含むエントリを返す。
+// どちらの場合も、都道府県名は省略してもよい。
+func (idx *Index) Search(addr string) []*Entry {
+	addr = normalizeAddress(addr)
+	if addr == "" {
+		return nil
+	}
+	var (
+		a []*Entry
+		n int
+	)
+	for _, entry := range idx.entries {
+		s := entry.Region.Text + entry.Town.Text
+		var m int
+		switch {
+		case strings.HasPrefix(addr, entry.Pref.Text+s):
+			m = len(s)
+		case strings.HasPrefix(addr, s):
+			m = len(s)
+		default:
+			continue
+		}
+		if m > n {
+			a = a[:0]
+			n = m
+		}
+		if m == n {
+			a = append(a, entry)
+		}
+	}
+	if len(a) > 0 {
+		return a
+	}
+	for _, entry := range idx.entries {
+		s := entry.Pref.Text + entry.Region.Text + entry.Town.Text
+		if strings.Contains(s, addr) {
+			a = append(a, entry)
+		}
+	}
+	sort.SliceStable(a, func(i, j int) bool {
+		return a[i].Zip < a[j].Zip
+	})
+	return a
+}
+
+// normalizeAddressは住所の空白を取り除き、英数字や記号をASCII文字に統一する。
+func normalizeAddress(s string) string {
+	s = strings.Map(func(c rune) rune {
+		switch c {
+		case ' ', '　', '\t':
+			return -1
+		}
+		return c
+	}, s)
+	return normalizeText(s)
+}
@@ -0,0 +1,69 @@
+package zipcode
+
+import (
+	"strings"
+	"testing"
+)
+
+var testIndexData = []string{
+	`13104,"160  ","1600000","ﾄｳｷｮｳﾄ","ｼﾝｼﾞｭｸｸ","ｲｶﾆｹｲｻｲｶﾞﾅｲﾊﾞｱｲ","東京都","新宿区","以下に掲載がない場合",0,0,0,0,0,0`,
+	`13104,"160  ","1600023","ﾄｳｷｮｳﾄ","ｼﾝｼﾞｭｸｸ","ﾆｼｼﾝｼﾞｭｸ(ﾂｷﾞﾉﾋﾞﾙｦﾉｿﾞｸ)","東京都","新宿区","西新宿（次のビルを除く）",0,0,1,0,0,0`,
+	`13104,"163  ","1630890","ﾄｳｷｮｳﾄ","ｼﾝｼﾞｭｸｸ","ﾆｼｼﾝｼﾞｭｸｼﾝｼﾞｭｸﾊﾟｰｸﾀﾜｰ(ﾁｶｲ･ｶｲｿｳﾌﾒｲ)","東京都","新宿区","西新宿新宿パークタワー（地階・階層不明）",0,0,0,0,0,0`,
+	`13104,"162  ","1620843","ﾄｳｷｮｳﾄ","ｼﾝｼﾞｭｸｸ","ｲﾁｶﾞﾔﾀﾏﾁ","東京都","新宿区","市谷田町",0,0,1,0,0,0`,
+	`01101,"064  ","0640941","ﾎｯｶｲﾄﾞｳ","ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ","ｱｻﾋｶﾞｵｶ","北海道","札幌市中央区","旭ケ丘",0,0,1,0,0,0`,
+	`23105,"450  ","4500002","ｱｲﾁｹﾝ","ﾅｺﾞﾔｼﾅｶﾑﾗｸ","ﾒｲｴｷ","愛知県","名古屋市中村区","名駅",0,0,1,0,0,0`,
+}
+
+func testIndex(t *testing.T) *Index {
+	t.Helper()
+	idx, err := ReadIndex(strings.NewReader(strings.Join(testIndexData, "\n")))
+	if err != nil {
+		t.Fatalf("ReadIndex() = %v", err)
+	}
+	return idx
+}
+
+func TestIndexLookup(t *testing.T) {
+	idx := testIndex(t)
+	tests := []struct {
+		zip  string
+		town string
+	}{
+		{"1600023", "西新宿"},
+		{"160-0023", "西新宿"},
+		{"〒１６０−００２３", "西新宿"},
+	}
+	for _, tt := range tests {
+		a := idx.Lookup(tt.zip)
+		if len(a) != 1 || a[0].Town.Text != tt.town {
+			t.Errorf("Lookup(%q) = %v; Expect %s", tt.zip, a, tt.town)
+		}
+	}
+	if a := idx.Lookup("9999999"); len(a) != 0 {
+		t.Errorf("Lookup(%q) = %v; Expect empty", "9999999", a)
+	}
+}
+
+func TestIndexSearch(t *testing.T) {
+	idx := testIndex(t)
+	tests := []struct {
+		addr string
+		zips []string
+	}{
+		{"東京都新宿区西新宿２−８−１", []string{"1600023"}},
+		{"新宿区西新宿", []string{"1600023"}},
+		{"東京都新宿区歌舞伎町", []string{"1600000"}},
+		{"新宿", []string{"1600000", "1600023", "1620843", "1630890"}},
+		{"大阪府", nil},
+	}
+	for _, tt := range tests {
+		a := idx.Search(tt.addr)
+		var zips []string
+		for _, entry := range a {
+			zips = append(zips, string(entry.Zip))
+		}
+		if strings.Join(zips, ",") != strings.Join(tt.zips, ",") {
+			t.Errorf("Search(%q) = %v; Expect %v", tt.addr, zips, tt.zips)
+		}
+	}
+}
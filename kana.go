@@ -0,0 +1,89 @@
+package zipcode
+
+import (
+	"strings"
+)
+
+const (
+	halfKana = "｡｢｣､･ｦｧｨｩｪｫｬｭｮｯｰｱｲｳｴｵｶｷｸｹｺｻｼｽｾｿﾀﾁﾂﾃﾄﾅﾆﾇﾈﾉﾊﾋﾌﾍﾎﾏﾐﾑﾒﾓﾔﾕﾖﾗﾘﾙﾚﾛﾜﾝ"
+	fullKana = "。「」、・ヲァィゥェォャュョッーアイウエオカキクケコサシスセソタチツテトナニヌネノハヒフヘホマミムメモヤユヨラリルレロワン"
+)
+
+var halfToFullKana = func() map[rune]rune {
+	m := make(map[rune]rune)
+	full := []rune(fullKana)
+	for i, c := range []rune(halfKana) {
+		m[c] = full[i]
+	}
+	return m
+}()
+
+// toKatakanaはsに含まれる半角カタカナを全角カタカナに変換する。
+// 濁点と半濁点は直前の文字と合成する。
+func toKatakana(s string) string {
+	var buf strings.Builder
+	r := []rune(s)
+	for i := 0; i < len(r); i++ {
+		c, ok := halfToFullKana[r[i]]
+		if !ok {
+			buf.WriteRune(r[i])
+			continue
+		}
+		if i+1 < len(r) {
+			switch r[i+1] {
+			case 'ﾞ':
+				if c1, ok := voiced(c, 1); ok {
+					c = c1
+					i++
+				}
+			case 'ﾟ':
+				if c1, ok := voiced(c, 2); ok {
+					c = c1
+					i++
+				}
+			}
+		}
+		buf.WriteRune(c)
+	}
+	return buf.String()
+}
+
+// voicedは全角カタカナcに濁点(n=1)または半濁点(n=2)を付けた文字を返す。
+func voiced(c rune, n int) (rune, bool) {
+	switch {
+	case c == 'ウ' && n == 1:
+		return 'ヴ', true
+	case c >= 'カ' && c <= 'ト' && n == 1:
+		// カ行からタ行は清音と濁音が交互に並ぶが、ッだけは例外。
+		if c >= 'ツ' {
+			return c + 1, (c-'ツ')%2 == 0
+		}
+		return c + 1, (c-'カ')%2 == 0
+	case c >= 'ハ' && c <= 'ホ':
+		return c + rune(n), (c-'ハ')%3 == 0
+	}
+	return c, false
+}
+
+// toHiraganaはsに含まれる半角または全角のカタカナをひらがなに変換する。
+func toHiragana(s string) string {
+	return strings.Map(func(c rune) rune {
+		switch {
+		case c >= 'ァ' && c <= 'ン':
+			return c - 'ァ' + 'ぁ'
+		case c == 'ヴ':
+			return 'ゔ'
+		}
+		return c
+	}, toKatakana(s))
+}
+
+// Katakanaはカナ表記の名前を全角カタカナで返す。
+func (name Name) Katakana() string {
+	return toKatakana(name.Ruby)
+}
+
+// Hiraganaはカナ表記の名前をひらがなで返す。
+func (name Name) Hiragana() string {
+	return toHiragana(name.Ruby)
+}
@@ -0,0 +1,30 @@
+package zipcode
+
+import (
+	"testing"
+)
+
+func TestNameKana(t *testing.T) {
+	tests := []struct {
+		ruby     string
+		katakana string
+		hiragana string
+	}{
+		{"ﾄｳｷｮｳﾄ", "トウキョウト", "とうきょうと"},
+		{"ｼﾝｼﾞｭｸｸ", "シンジュクク", "しんじゅくく"},
+		{"ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ", "サッポロシチュウオウク", "さっぽろしちゅうおうく"},
+		{"ﾂﾞﾂﾞｶﾞﾀﾞﾃﾞﾄﾞﾊﾟﾋﾟﾌﾟﾍﾟﾎﾟﾊﾞﾋﾞﾌﾞﾍﾞﾎﾞ", "ヅヅガダデドパピプペポバビブベボ", "づづがだでどぱぴぷぺぽばびぶべぼ"},
+		{"ｳﾞｧｲｵﾘﾝ", "ヴァイオリン", "ゔぁいおりん"},
+		{"ﾒｲｴｷﾐｯﾄﾞﾗﾝﾄﾞｽｸｴｱ47ｶｲ", "メイエキミッドランドスクエア47カイ", "めいえきみっどらんどすくえあ47かい"},
+		{"ｱﾞﾝﾟ", "アﾞンﾟ", "あﾞんﾟ"},
+	}
+	for _, tt := range tests {
+		name := Name{Ruby: tt.ruby}
+		if s := name.Katakana(); s != tt.katakana {
+			t.Errorf("Katakana(%q) = %q; Expect %q", tt.ruby, s, tt.katakana)
+		}
+		if s := name.Hiragana(); s != tt.hiragana {
+			t.Errorf("Hiragana(%q) = %q; Expect %q", tt.ruby, s, tt.hiragana)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package zipcode
+
+import (
+	"strconv"
+	"strings"
+)
+
+// kanjiDigitsは漢数字の数字。
+var kanjiDigits = map[rune]int{
+	'〇': 0, '零': 0,
+	'一': 1, '壱': 1,
+	'二': 2, '弐': 2,
+	'三': 3, '参': 3,
+	'四': 4,
+	'五': 5,
+	'六': 6,
+	'七': 7,
+	'八': 8,
+	'九': 9,
+}
+
+// kanjiUnitsは漢数字の位取りの文字。
+var kanjiUnits = map[rune]int{
+	'十': 10,
+	'百': 100,
+	'千': 1000,
+}
+
+func isKanjiNumeral(c rune) bool {
+	_, ok1 := kanjiDigits[c]
+	_, ok2 := kanjiUnits[c]
+	return ok1 || ok2
+}
+
+// parseKanjiNumberは漢数字sの値を返す。
+// "三十五"のような位取りと"二〇三"のような数字の並びの両方を受け付ける。
+func parseKanjiNumber(s []rune) (int, bool) {
+	if len(s) == 0 {
+		return 0, false
+	}
+	var (
+		total int
+		n     = -1 // 位取りの文字を待っている数字。なければ-1。
+		units bool
+	)
+	for _, c := range s {
+		if d, ok := kanjiDigits[c]; ok {
+			if n >= 0 {
+				if units {
+					return 0, false
+				}
+				// 位取りを使わない数字の並び。
+				n = n*10 + d
+			} else {
+				n = d
+			}
+			continue
+		}
+		u, ok := kanjiUnits[c]
+		if !ok {
+			return 0, false
+		}
+		units = true
+		if n < 0 {
+			n = 1
+		}
+		total += n * u
+		n = -1
+	}
+	if n >= 0 {
+		total += n
+	}
+	return total, true
+}
+
+// kanjiCountersは町域名で数を数える語。漢数字はこれらの直前にある場合だけ算用数字にする。
+// 長いものから順に並べる。
+var kanjiCounters = []string{"丁目", "地割", "番地", "条", "線", "番", "号"}
+
+// NormalizeKanjiNumeralsは町域名sに含まれる"三十条"や"第二地割"などの漢数字を算用数字にした文字列を返す。
+// "一宮"や"九段"のような地名の一部を変換しないように、
+// 丁目、条、線、地割、番地、番、号の直前にある漢数字だけを変換する。
+func NormalizeKanjiNumerals(s string) string {
+	r := []rune(s)
+	var buf strings.Builder
+	for i := 0; i < len(r); i++ {
+		if !isKanjiNumeral(r[i]) {
+			buf.WriteRune(r[i])
+			continue
+		}
+		j := i
+		for j < len(r) && isKanjiNumeral(r[j]) {
+			j++
+		}
+		v, ok := parseKanjiNumber(r[i:j])
+		if ok && hasCounterPrefix(string(r[j:])) {
+			buf.WriteString(strconv.Itoa(v))
+		} else {
+			buf.WriteString(string(r[i:j]))
+		}
+		i = j - 1
+	}
+	return buf.String()
+}
+
+func hasCounterPrefix(s string) bool {
+	for _, c := range kanjiCounters {
+		if strings.HasPrefix(s, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// kanjiNumeralsは0から9までの漢数字。
+const kanjiNumerals = "〇一二三四五六七八九"
+
+// KanjiNumeralsはsに含まれる算用数字を縦書き用の漢数字にした文字列を返す。
+// 4桁までの数は"二十三"や"二百三"のように位取りで書き、
+// 5桁以上の数や0で始まる数は"一六〇"のように1桁ずつ書く。
+func KanjiNumerals(s string) string {
+	var buf strings.Builder
+	for len(s) > 0 {
+		i := strings.IndexFunc(s, isDigit)
+		if i < 0 {
+			buf.WriteString(s)
+			break
+		}
+		buf.WriteString(s[:i])
+		s = s[i:]
+		n := strings.IndexFunc(s, func(c rune) bool { return !isDigit(c) })
+		if n < 0 {
+			n = len(s)
+		}
+		buf.WriteString(kanjiNumber(s[:n]))
+		s = s[n:]
+	}
+	return buf.String()
+}
+
+// kanjiNumberは算用数字だけの文字列sを漢数字にする。
+func kanjiNumber(s string) string {
+	digits := []rune(kanjiNumerals)
+	var buf strings.Builder
+	if len(s) > 4 || len(s) > 1 && s[0] == '0' {
+		for _, c := range s {
+			buf.WriteRune(digits[c-'0'])
+		}
+		return buf.String()
+	}
+	if s == "0" {
+		return string(digits[0])
+	}
+	units := []string{"千", "百", "十", ""}[4-len(s):]
+	for i, c := range s {
+		switch {
+		case c == '0':
+		case c == '1' && units[i] != "":
+			buf.WriteString(units[i])
+		default:
+			buf.WriteRune(digits[c-'0'])
+			buf.WriteString(units[i])
+		}
+	}
+	return buf.String()
+}
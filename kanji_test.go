@@ -0,0 +1,69 @@
+package zipcode
+
+import (
+	"testing"
+)
+
+func TestParseKanjiNumber(t *testing.T) {
+	tests := []struct {
+		s string
+		n int
+	}{
+		{"一", 1},
+		{"十", 10},
+		{"十五", 15},
+		{"三十", 30},
+		{"百二", 102},
+		{"千二百三十四", 1234},
+		{"二〇三", 203},
+	}
+	for _, tt := range tests {
+		n, ok := parseKanjiNumber([]rune(tt.s))
+		if !ok || n != tt.n {
+			t.Errorf("parseKanjiNumber(%q) = %d, %t; Expect %d", tt.s, n, ok, tt.n)
+		}
+	}
+}
+
+func TestNormalizeKanjiNumerals(t *testing.T) {
+	tests := []struct {
+		s      string
+		expect string
+	}{
+		{"南三十条西9丁目", "南30条西9丁目"},
+		{"三丁目", "3丁目"},
+		{"川井第十一地割", "川井第11地割"},
+		{"東十二線", "東12線"},
+		{"二番町", "2番町"},
+		{"一宮", "一宮"},
+		{"九段北", "九段北"},
+		{"十勝", "十勝"},
+	}
+	for _, tt := range tests {
+		if s := NormalizeKanjiNumerals(tt.s); s != tt.expect {
+			t.Errorf("NormalizeKanjiNumerals(%q) = %q; Expect %q", tt.s, s, tt.expect)
+		}
+	}
+}
+
+func TestKanjiNumerals(t *testing.T) {
+	tests := []struct {
+		s      string
+		expect string
+	}{
+		{"1", "一"},
+		{"10", "十"},
+		{"15", "十五"},
+		{"203", "二百三"},
+		{"1000", "千"},
+		{"0", "〇"},
+		{"0023", "〇〇二三"},
+		{"1600023", "一六〇〇〇二三"},
+		{"2丁目8番1号", "二丁目八番一号"},
+	}
+	for _, tt := range tests {
+		if s := KanjiNumerals(tt.s); s != tt.expect {
+			t.Errorf("KanjiNumerals(%q) = %q; Expect %q", tt.s, s, tt.expect)
+		}
+	}
+}
@@ -24,11 +24,11 @@ type Entry struct {
 	// 全国地方公共団体コード。
 	Code string
 
-	// 旧郵便番号(5桁)。
-	OldZip string
+	// 旧郵便番号(3桁または5桁)。
+	OldZip Zip
 
 	// 郵便番号(7桁)。
-	Zip string
+	Zip Zip
 
 	// 都道府県名。
 	Pref Name
@@ -36,9 +36,23 @@ type Entry struct {
 	// 市区町村名。
 	Region Name
 
+	// 市区町村名のうち郡の部分。"吾川郡いの町"なら"吾川郡"。
+	District Name
+
+	// 市区町村名のうち市町村の部分。"吾川郡いの町"なら"いの町"、"札幌市中央区"なら"札幌市"。
+	// 東京都の特別区では空。
+	City Name
+
+	// 市区町村名のうち区の部分。"札幌市中央区"なら"中央区"、"新宿区"なら"新宿区"。
+	Ward Name
+
 	// 町域名。
 	Town Name
 
+	// 照合用の町域名。KanjiNumeralFilterを通した場合に、
+	// Town.Textの漢数字を算用数字にした文字列がセットされる。
+	TownKey string
+
 	// 町域が2つ以上の郵便番号を持つ。
 	IsPartialTown bool
 
@@ -61,6 +75,9 @@ type Entry struct {
 
 	// 備考。このフィールドはKEN_ALL.CSVには存在しない。
 	Notice string
+
+	// KEN_ALL.CSVでの行番号。複数行を連結した場合は最初の行。
+	line int
 }
 
 // ルビ付き名前を表す。
@@ -0,0 +1,79 @@
+package zipcode
+
+import (
+	"strings"
+)
+
+// HyphenatedZipは郵便番号を"160-0023"の形式で返す。
+func (entry *Entry) HyphenatedZip() string {
+	return entry.Zip.Hyphenated()
+}
+
+// Addressは都道府県名から町域名までを連結した"東京都新宿区西新宿"の形式の住所を返す。
+func (entry *Entry) Address() string {
+	return entry.Pref.Text + entry.Region.Text + entry.Town.Text
+}
+
+// ShortAddressはAddressと同じだが、政令指定都市の区では"札幌市中央区旭ケ丘"のように都道府県名を省く。
+// 東京都の特別区など、市区町村名だけでは場所が分かりにくい場合は都道府県名を残す。
+func (entry *Entry) ShortAddress() string {
+	if !entry.isDesignatedCity() {
+		return entry.Address()
+	}
+	return entry.Region.Text + entry.Town.Text
+}
+
+// isDesignatedCityはentryが政令指定都市の区かどうかを返す。
+func (entry *Entry) isDesignatedCity() bool {
+	if len(entry.Code) != 5 || !isDigits(entry.Code) || entry.Code[2] != '1' {
+		return false
+	}
+	if _, ok := designatedCities[entry.Code]; ok {
+		return false
+	}
+	_, ok := designatedCityCode(entry.Code)
+	return ok
+}
+
+// LabelOptionsは宛名ラベルの書き方を指定する。
+type LabelOptions struct {
+	// trueなら政令指定都市の区では都道府県名を省く。
+	OmitPref bool
+
+	// trueなら縦書き用に、数字をすべて漢数字で書く。
+	Vertical bool
+}
+
+// Labelは宛名ラベルに印字する住所を
+//
+//	〒160-0023
+//	東京都新宿区西新宿2丁目8番1号
+//	都庁第一本庁舎
+//
+// のように1行ずつ返す。streetの空のフィールドは省く。
+func (entry *Entry) Label(street Street, opts LabelOptions) string {
+	addr := entry.Address()
+	if opts.OmitPref {
+		addr = entry.ShortAddress()
+	}
+	lines := []string{addr + street.String()}
+	if street.Building != "" {
+		lines = append(lines, street.Building)
+	}
+	s := strings.Join(lines, "\n")
+	zip := entry.HyphenatedZip()
+	if opts.Vertical {
+		s = strings.Replace(KanjiNumerals(s), "-", "－", -1)
+		// 郵便番号は位取りをせずに1桁ずつ書く。
+		zip = strings.Map(func(c rune) rune {
+			if isDigit(c) {
+				return []rune(kanjiNumerals)[c-'0']
+			}
+			if c == '-' {
+				return '－'
+			}
+			return c
+		}, zip)
+	}
+	return "〒" + zip + "\n" + s
+}
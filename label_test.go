@@ -0,0 +1,61 @@
+package zipcode
+
+import (
+	"testing"
+)
+
+func TestLabel(t *testing.T) {
+	idx := testIndex(t)
+	street := Street{Chome: "2", Banchi: "8", Go: "1", Building: "都庁第一本庁舎"}
+	tests := []struct {
+		zip    string
+		street Street
+		opts   LabelOptions
+		expect string
+	}{
+		{"1600023", street, LabelOptions{}, "〒160-0023\n東京都新宿区西新宿2丁目8番1号\n都庁第一本庁舎"},
+		{"1600023", street, LabelOptions{OmitPref: true}, "〒160-0023\n東京都新宿区西新宿2丁目8番1号\n都庁第一本庁舎"},
+		{"1600023", street, LabelOptions{Vertical: true}, "〒一六〇－〇〇二三\n東京都新宿区西新宿二丁目八番一号\n都庁第一本庁舎"},
+		{"0640941", Street{Chome: "3", Banchi: "15"}, LabelOptions{OmitPref: true, Vertical: true}, "〒〇六四－〇九四一\n札幌市中央区旭ケ丘三丁目十五番地"},
+		{"4500002", Street{}, LabelOptions{}, "〒450-0002\n愛知県名古屋市中村区名駅"},
+	}
+	for _, tt := range tests {
+		a := idx.Lookup(tt.zip)
+		if len(a) != 1 {
+			t.Fatalf("Lookup(%q) = %v", tt.zip, a)
+		}
+		if s := a[0].Label(tt.street, tt.opts); s != tt.expect {
+			t.Errorf("Label(%v, %v) = %q; Expect %q", tt.street, tt.opts, s, tt.expect)
+		}
+	}
+}
+
+func TestShortAddress(t *testing.T) {
+	idx := testIndex(t)
+	tests := []struct {
+		zip    string
+		expect string
+	}{
+		{"1600023", "東京都新宿区西新宿"},
+		{"0640941", "札幌市中央区旭ケ丘"},
+		{"4500002", "名古屋市中村区名駅"},
+	}
+	for _, tt := range tests {
+		a := idx.Lookup(tt.zip)
+		if s := a[0].ShortAddress(); s != tt.expect {
+			t.Errorf("ShortAddress() = %q; Expect %q", s, tt.expect)
+		}
+	}
+
+	// 区のコードが0で終わる政令指定都市の区。
+	wards := []*Entry{
+		{Code: "01110", Pref: Name{Text: "北海道"}, Region: Name{Text: "札幌市清田区"}, Town: Name{Text: "清田一条"}},
+		{Code: "14110", Pref: Name{Text: "神奈川県"}, Region: Name{Text: "横浜市戸塚区"}, Town: Name{Text: "戸塚町"}},
+		{Code: "28110", Pref: Name{Text: "兵庫県"}, Region: Name{Text: "神戸市中央区"}, Town: Name{Text: "港島"}},
+	}
+	for _, entry := range wards {
+		if s, expect := entry.ShortAddress(), entry.Region.Text+entry.Town.Text; s != expect {
+			t.Errorf("ShortAddress() = %q; Expect %q", s, expect)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package zipcode
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 市区町村の種類を表す。
+//
+// 郡は地方公共団体ではなく全国地方公共団体コードを持たないため、種類には含めない。
+// 郡に属する町村では、Municipality.Districtに郡の名前を持つ。
+type MunicipalityKind int
+
+const (
+	// 政令指定都市。
+	KindDesignatedCity MunicipalityKind = 1
+
+	// 政令指定都市の区、または東京都の特別区。
+	KindWard MunicipalityKind = 2
+
+	// 市。
+	KindCity MunicipalityKind = 3
+
+	// 町。
+	KindTown MunicipalityKind = 4
+
+	// 村。
+	KindVillage MunicipalityKind = 5
+)
+
+var municipalityKindNames = map[MunicipalityKind]string{
+	KindDesignatedCity: "政令指定都市",
+	KindWard:           "区",
+	KindCity:           "市",
+	KindTown:           "町",
+	KindVillage:        "村",
+}
+
+func (kind MunicipalityKind) String() string {
+	if s, ok := municipalityKindNames[kind]; ok {
+		return s
+	}
+	return fmt.Sprintf("MunicipalityKind(%d)", int(kind))
+}
+
+// designatedCitiesは政令指定都市の名前。
+// KEN_ALL.CSVには区のエントリしかないため、市の名前はここから得る。
+var designatedCities = map[string]Name{
+	"01100": {"札幌市", "ｻｯﾎﾟﾛｼ"},
+	"04100": {"仙台市", "ｾﾝﾀﾞｲｼ"},
+	"11100": {"さいたま市", "ｻｲﾀﾏｼ"},
+	"12100": {"千葉市", "ﾁﾊﾞｼ"},
+	"14100": {"横浜市", "ﾖｺﾊﾏｼ"},
+	"14130": {"川崎市", "ｶﾜｻｷｼ"},
+	"14150": {"相模原市", "ｻｶﾞﾐﾊﾗｼ"},
+	"15100": {"新潟市", "ﾆｲｶﾞﾀｼ"},
+	"22100": {"静岡市", "ｼｽﾞｵｶｼ"},
+	"22130": {"浜松市", "ﾊﾏﾏﾂｼ"},
+	"23100": {"名古屋市", "ﾅｺﾞﾔｼ"},
+	"26100": {"京都市", "ｷｮｳﾄｼ"},
+	"27100": {"大阪市", "ｵｵｻｶｼ"},
+	"27140": {"堺市", "ｻｶｲｼ"},
+	"28100": {"神戸市", "ｺｳﾍﾞｼ"},
+	"33100": {"岡山市", "ｵｶﾔﾏｼ"},
+	"34100": {"広島市", "ﾋﾛｼﾏｼ"},
+	"40100": {"北九州市", "ｷﾀｷｭｳｼｭｳｼ"},
+	"40130": {"福岡市", "ﾌｸｵｶｼ"},
+	"43100": {"熊本市", "ｸﾏﾓﾄｼ"},
+}
+
+// 東京都の特別区をまとめた特別区部のコード。
+const tokyoWardsCode = "13100"
+
+// 市区町村を表す。
+type Municipality struct {
+	// 全国地方公共団体コード(5桁)。
+	Code string
+
+	// 都道府県。
+	Pref Prefecture
+
+	// 市区町村名。政令指定都市の区は"札幌市中央区"のように市の名前を含む。
+	Name Name
+
+	// 郡に属する町村なら郡の名前。それ以外は空。
+	District Name
+
+	// 市区町村の種類。
+	Kind MunicipalityKind
+}
+
+// FullCodeは検査数字を付けた6桁の全国地方公共団体コードを返す。
+func (m Municipality) FullCode() string {
+	s, _ := MunicipalityCode(m.Code)
+	return s
+}
+
+// municipalityKindは全国地方公共団体コードと名前から市区町村の種類を返す。
+func municipalityKind(code, name string) MunicipalityKind {
+	_, designated := designatedCities[code]
+	switch {
+	case code[2] == '1' && code[:2] == "13":
+		return KindWard
+	case designated:
+		return KindDesignatedCity
+	case code[2] == '1':
+		return KindWard
+	case code[2] == '2':
+		return KindCity
+	case strings.HasSuffix(name, "村"):
+		return KindVillage
+	default:
+		return KindTown
+	}
+}
+
+// designatedCityCodesはdesignatedCitiesのコードを昇順に並べたもの。
+var designatedCityCodes = func() []string {
+	a := make([]string, 0, len(designatedCities))
+	for code := range designatedCities {
+		a = append(a, code)
+	}
+	sort.Strings(a)
+	return a
+}()
+
+// designatedCityCodeは政令指定都市の区のコードから市のコードを返す。
+// 区のコードは市のコードに続けて振られるため、同じ都道府県でcode以下の最も大きい市のコードを選ぶ。
+// 該当する市がdesignatedCitiesになければfalseを返す。
+func designatedCityCode(code string) (string, bool) {
+	city := ""
+	for _, c := range designatedCityCodes {
+		if c[:2] == code[:2] && c <= code {
+			city = c
+		}
+	}
+	return city, city != ""
+}
+
+// checkDigitはJIS X 0402の方法で5桁のコードの検査数字を返す。
+func checkDigit(code string) (byte, error) {
+	if len(code) != 5 || !isDigits(code) {
+		return 0, fmt.Errorf("%q is not 5 digits", code)
+	}
+	sum := 0
+	for i := 0; i < 5; i++ {
+		sum += int(code[i]-'0') * (6 - i)
+	}
+	return byte('0' + (11-sum%11)%10), nil
+}
+
+// MunicipalityCodeは5桁の全国地方公共団体コードに検査数字を付けた6桁のコードを返す。
+func MunicipalityCode(code string) (string, error) {
+	c, err := checkDigit(code)
+	if err != nil {
+		return "", err
+	}
+	return code + string(c), nil
+}
+
+// ValidMunicipalityCodeは、sが正しい検査数字を持つ6桁の全国地方公共団体コードならtrueを返す。
+func ValidMunicipalityCode(s string) bool {
+	if len(s) != 6 {
+		return false
+	}
+	c, err := checkDigit(s[:5])
+	return err == nil && s[5] == c
+}
+
+// municipalityTableは市区町村をコードから引く表。
+type municipalityTable struct {
+	byCode map[string]Municipality
+	codes  []string
+}
+
+func newMunicipalityTable(entries []*Entry) *municipalityTable {
+	t := &municipalityTable{byCode: make(map[string]Municipality)}
+	add := func(code string, name Name) {
+		if _, ok := t.byCode[code]; ok {
+			return
+		}
+		pref, _ := LookupPrefecture(code[:2])
+		t.byCode[code] = Municipality{
+			Code: code,
+			Pref: pref,
+			Name: name,
+			Kind: municipalityKind(code, name.Text),
+		}
+		if m := t.byCode[code]; m.Kind == KindTown || m.Kind == KindVillage {
+			m.District, _ = splitDistrict(name)
+			t.byCode[code] = m
+		}
+		t.codes = append(t.codes, code)
+	}
+	for _, entry := range entries {
+		if len(entry.Code) != 5 || !isDigits(entry.Code) {
+			continue
+		}
+		if municipalityKind(entry.Code, entry.Region.Text) == KindWard && entry.Code[:2] != "13" {
+			// 表にない政令指定都市は、市のコードが分からないので区だけを追加する。
+			if code, ok := designatedCityCode(entry.Code); ok {
+				add(code, designatedCities[code])
+			}
+		}
+		add(entry.Code, entry.Region)
+	}
+	sort.Strings(t.codes)
+	return t
+}
+
+// Municipalityは全国地方公共団体コードcodeの市区町村を返す。
+// codeは5桁でも、検査数字を付けた6桁でもよい。
+func (idx *Index) Municipality(code string) (Municipality, bool) {
+	if len(code) == 6 {
+		if !ValidMunicipalityCode(code) {
+			return Municipality{}, false
+		}
+		code = code[:5]
+	}
+	m, ok := idx.cities.byCode[code]
+	return m, ok
+}
+
+// Municipalitiesは都道府県prefに属する市区町村をコード順に返す。
+// prefはLookupPrefectureが受け付ける形式で指定する。
+// 政令指定都市は、市とその区の両方を含む。
+func (idx *Index) Municipalities(pref string) []Municipality {
+	p, ok := LookupPrefecture(pref)
+	if !ok {
+		return nil
+	}
+	var a []Municipality
+	for _, code := range idx.cities.codes {
+		if strings.HasPrefix(code, p.Code) {
+			a = append(a, idx.cities.byCode[code])
+		}
+	}
+	return a
+}
+
+// Wardsは政令指定都市cityの区をコード順に返す。
+// cityが"13100"(特別区部)なら東京都の特別区を返す。
+func (idx *Index) Wards(city string) []Municipality {
+	if len(city) == 6 {
+		city = city[:5]
+	}
+	var a []Municipality
+	for _, code := range idx.cities.codes {
+		m := idx.cities.byCode[code]
+		if m.Kind != KindWard {
+			continue
+		}
+		if city == tokyoWardsCode && code[:2] == "13" {
+			a = append(a, m)
+			continue
+		}
+		if c, ok := designatedCityCode(code); ok && c == city {
+			a = append(a, m)
+		}
+	}
+	return a
+}
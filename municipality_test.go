@@ -0,0 +1,125 @@
+package zipcode
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMunicipalityCode(t *testing.T) {
+	tests := []struct {
+		code   string
+		expect string
+	}{
+		{"01100", "011002"},
+		{"01101", "011011"},
+		{"13104", "131041"},
+		{"13113", "131130"},
+		{"23105", "231053"},
+	}
+	for _, tt := range tests {
+		s, err := MunicipalityCode(tt.code)
+		if err != nil || s != tt.expect {
+			t.Errorf("MunicipalityCode(%q) = %q, %v; Expect %q", tt.code, s, err, tt.expect)
+		}
+		if !ValidMunicipalityCode(tt.expect) {
+			t.Errorf("ValidMunicipalityCode(%q) = false; Expect true", tt.expect)
+		}
+	}
+	for _, s := range []string{"131042", "13104", "1310a1"} {
+		if ValidMunicipalityCode(s) {
+			t.Errorf("ValidMunicipalityCode(%q) = true; Expect false", s)
+		}
+	}
+}
+
+func TestIndexMunicipalities(t *testing.T) {
+	idx := testIndex(t)
+	tests := []struct {
+		pref   string
+		expect []Municipality
+	}{
+		{
+			pref: "北海道",
+			expect: []Municipality{
+				{Code: "01100", Name: Name{"札幌市", "ｻｯﾎﾟﾛｼ"}, Kind: KindDesignatedCity},
+				{Code: "01101", Name: Name{"札幌市中央区", "ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ"}, Kind: KindWard},
+			},
+		},
+		{
+			pref: "13",
+			expect: []Municipality{
+				{Code: "13104", Name: Name{"新宿区", "ｼﾝｼﾞｭｸｸ"}, Kind: KindWard},
+			},
+		},
+	}
+	for _, tt := range tests {
+		a := idx.Municipalities(tt.pref)
+		if len(a) != len(tt.expect) {
+			t.Fatalf("Municipalities(%q) = %v; Expect %v", tt.pref, a, tt.expect)
+		}
+		for i, m := range a {
+			e := tt.expect[i]
+			if m.Code != e.Code || !m.Name.Equal(e.Name) || m.Kind != e.Kind || m.Pref.Code != e.Code[:2] {
+				t.Errorf("Municipalities(%q)[%d] = %v; Expect %v", tt.pref, i, m, e)
+			}
+		}
+	}
+
+	if m, ok := idx.Municipality("011011"); !ok || m.Code != "01101" {
+		t.Errorf("Municipality(%q) = %v, %t; Expect 01101", "011011", m, ok)
+	}
+	if wards := idx.Wards("01100"); len(wards) != 1 || wards[0].Code != "01101" {
+		t.Errorf("Wards(%q) = %v; Expect [01101]", "01100", wards)
+	}
+	if wards := idx.Wards("13100"); len(wards) != 1 || wards[0].Code != "13104" {
+		t.Errorf("Wards(%q) = %v; Expect [13104]", "13100", wards)
+	}
+}
+
+func TestDesignatedCityWards(t *testing.T) {
+	data := append([]string{
+		`01110,"004  ","0040831","ﾎｯｶｲﾄﾞｳ","ｻｯﾎﾟﾛｼｷﾖﾀｸ","ｷﾖﾀ1ｼﾞｮｳ","北海道","札幌市清田区","清田一条",0,0,1,0,0,0`,
+		`14111,"233  ","2330002","ｶﾅｶﾞﾜｹﾝ","ﾖｺﾊﾏｼｺｳﾅﾝｸ","ｶﾐｵｵｵｶﾆｼ","神奈川県","横浜市港南区","上大岡西",0,0,1,0,0,0`,
+		`27128,"540  ","5400002","ｵｵｻｶﾌ","ｵｵｻｶｼﾁｭｳｵｳｸ","ｵｵｻｶｼﾞｮｳ","大阪府","大阪市中央区","大阪城",0,0,0,0,0,0`,
+		`39386,"78121","7812110","ｺｳﾁｹﾝ","ｱｶﾞﾜｸﾞﾝｲﾉﾁｮｳ","ｲｶﾆｹｲｻｲｶﾞﾅｲﾊﾞｱｲ","高知県","吾川郡いの町","以下に掲載がない場合",0,0,0,0,0,0`,
+	}, testIndexData...)
+	idx, err := ReadIndex(strings.NewReader(strings.Join(data, "\n")))
+	if err != nil {
+		t.Fatalf("ReadIndex() = %v", err)
+	}
+	tests := []struct {
+		code string
+		kind MunicipalityKind
+		city string
+	}{
+		{"01100", KindDesignatedCity, ""},
+		{"01110", KindWard, "01100"},
+		{"14100", KindDesignatedCity, ""},
+		{"14111", KindWard, "14100"},
+		{"27100", KindDesignatedCity, ""},
+		{"27128", KindWard, "27100"},
+		{"39386", KindTown, ""},
+	}
+	for _, tt := range tests {
+		m, ok := idx.Municipality(tt.code)
+		if !ok || m.Kind != tt.kind {
+			t.Errorf("Municipality(%q) = %v, %t; Expect %v", tt.code, m, ok, tt.kind)
+		}
+		if tt.city == "" {
+			continue
+		}
+		found := false
+		for _, w := range idx.Wards(tt.city) {
+			found = found || w.Code == tt.code
+		}
+		if !found {
+			t.Errorf("Wards(%q) = %v; Expect to contain %s", tt.city, idx.Wards(tt.city), tt.code)
+		}
+	}
+	if wards := idx.Wards("01100"); len(wards) != 2 {
+		t.Errorf("Wards(%q) = %v; Expect [01101 01110]", "01100", wards)
+	}
+	if m, _ := idx.Municipality("39386"); !m.District.Equal(Name{"吾川郡", "ｱｶﾞﾜｸﾞﾝ"}) {
+		t.Errorf("Municipality(%q).District = %v; Expect 吾川郡", "39386", m.District)
+	}
+}
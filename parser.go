@@ -2,115 +2,197 @@ package zipcode
 
 import (
 	"encoding/csv"
-	"errors"
+	"fmt"
 	"io"
-	"strconv"
 	"strings"
 )
 
-type entryParser interface {
+// Filterは郵便番号データを加工するパイプラインの1段をあらわす。
+//
+// チャネルには*Entry、*Warningまたはerrorが流れる。
+// *Warningを受信した場合は、それをそのまま送信しなければならない。
+// errorを受信した場合は、それをそのまま送信して処理を終えなければならない。
+type Filter interface {
 	// Parseは、cから値を受信したあと必要な加工を行い、c1へ送信する。
+	// 処理を終えたらc1をcloseしなければならない。
 	Parse(c <-chan interface{}, c1 chan<- interface{})
 }
 
-type entryHandlerFunc func(entry *Entry) *Entry
+// EntryHandlerFuncはエントリを1つずつ加工するFilterである。
+type EntryHandlerFunc func(entry *Entry) *Entry
 
-func (f entryHandlerFunc) Parse(c <-chan interface{}, c1 chan<- interface{}) {
+func (f EntryHandlerFunc) Parse(c <-chan interface{}, c1 chan<- interface{}) {
 	defer close(c1)
 	for v := range c {
-		if err, ok := v.(error); ok {
-			c1 <- err
-			break
+		switch v := v.(type) {
+		case error:
+			c1 <- v
+			return
+		case *Warning:
+			c1 <- v
+		case *Entry:
+			c1 <- f(v)
 		}
-		c1 <- f(v.(*Entry))
 	}
 }
 
-var (
-	// 行連結が未解決のままとなった場合のエラー
-	incompleteEntry = errors.New("incomplete entry")
-)
+// EntryCollectorFuncは複数行に分割されたエントリを連結するFilterである。
+// entryが完結している場合はtrue、次のエントリと連結する場合はfalseを返す。
+type EntryCollectorFunc func(entry *Entry) bool
 
-// entryが完結している場合はtrue、次のエントリと連結する場合はfalse
-type entryCollectorFunc func(entry *Entry) bool
-
-func (f entryCollectorFunc) Parse(c <-chan interface{}, c1 chan<- interface{}) {
+func (f EntryCollectorFunc) Parse(c <-chan interface{}, c1 chan<- interface{}) {
 	defer close(c1)
+	var entry *Entry
 	for v := range c {
-		if err, ok := v.(error); ok {
-			c1 <- err
+		switch v := v.(type) {
+		case error:
+			c1 <- v
 			return
-		}
-		entry := v.(*Entry)
-		for !f(entry) {
-			v, ok := <-c
-			if !ok {
-				c1 <- incompleteEntry
-				return
+		case *Warning:
+			c1 <- v
+		case *Entry:
+			if entry == nil {
+				entry = v
+			} else {
+				entry.Town = entry.Town.combine(v.Town)
 			}
-			if err, ok := v.(error); ok {
-				c1 <- err
-				return
+			if f(entry) {
+				c1 <- entry
+				entry = nil
 			}
-			entry1 := v.(*Entry)
-			entry.Town = entry.Town.combine(entry1.Town)
 		}
+	}
+	if entry != nil {
+		c1 <- newWarning(entry, WarnIncompleteEntry, "incomplete entry")
 		c1 <- entry
 	}
 }
 
-type entryExpanderFunc func(entry *Entry) []*Entry
+// joinFilterは複数行に分割されたエントリを連結する。
+//
+// 町域名の括弧が閉じていない、または町域名が"、"で終わっている場合に、
+// 郵便番号、全国地方公共団体コード、IsPartialTownが同じである次のエントリと連結する。
+// 連結先が見つからない場合は、警告を送信してエントリをそのまま送信する。
+type joinFilter struct{}
 
-func (f entryExpanderFunc) Parse(c <-chan interface{}, c1 chan<- interface{}) {
+func (joinFilter) Parse(c <-chan interface{}, c1 chan<- interface{}) {
 	defer close(c1)
+	var entry *Entry
 	for v := range c {
-		if err, ok := v.(error); ok {
-			c1 <- err
+		switch v := v.(type) {
+		case error:
+			c1 <- v
 			return
+		case *Warning:
+			c1 <- v
+		case *Entry:
+			switch {
+			case entry == nil:
+				entry = v
+			case isSameRecord(entry, v):
+				entry.Town = entry.Town.combine(v.Town)
+			default:
+				c1 <- newWarning(entry, WarnIncompleteEntry, "next record has different key")
+				c1 <- entry
+				entry = v
+			}
+			if !isContinued(entry.Town.Text) {
+				c1 <- entry
+				entry = nil
+			}
 		}
-		entry := v.(*Entry)
-		remapRangeVerb(&entry.Town)
-		a1, err := textRule.Eval(entry.Town.Text)
-		if err != nil {
-			c1 <- err
-			return
+	}
+	if entry != nil {
+		c1 <- newWarning(entry, WarnIncompleteEntry, "no more records")
+		c1 <- entry
+	}
+}
+
+// isSameRecordは、entry1がentryの続きとなりうるかどうかを返す。
+func isSameRecord(entry, entry1 *Entry) bool {
+	return entry.Zip == entry1.Zip &&
+		entry.Code == entry1.Code &&
+		entry.IsPartialTown == entry1.IsPartialTown
+}
+
+// isContinuedは、町域名sが次の行へ続いている場合にtrueを返す。
+func isContinued(s string) bool {
+	return bracketDepth(s) > 0 || strings.HasSuffix(s, "、")
+}
+
+// bracketDepthは、sで閉じられていない括弧の数を返す。
+// 全角と半角の丸括弧、およびかぎ括弧を数える。
+func bracketDepth(s string) int {
+	depth := 0
+	for _, c := range s {
+		switch c {
+		case '(', '（', '「':
+			depth++
+		case ')', '）', '」':
+			depth--
 		}
-		a2, err := rubyRule.Eval(entry.Town.Ruby)
-		if err != nil {
-			c1 <- err
+	}
+	return depth
+}
+
+// EntryExpanderFuncは1つのエントリを複数のエントリに展開するFilterである。
+type EntryExpanderFunc func(entry *Entry) ([]*Entry, error)
+
+func (f EntryExpanderFunc) Parse(c <-chan interface{}, c1 chan<- interface{}) {
+	defer close(c1)
+	for v := range c {
+		switch v := v.(type) {
+		case error:
+			c1 <- v
 			return
+		case *Warning:
+			c1 <- v
+		case *Entry:
+			a, err := f(v)
+			if err != nil {
+				c1 <- err
+				return
+			}
+			for _, entry := range a {
+				c1 <- entry
+			}
 		}
+	}
+}
+
+// EntryCheckerFuncはエントリを検査するFilterである。
+// 異常が見つかった場合はWarningを返し、エントリは加工せずにそのまま送信する。
+type EntryCheckerFunc func(entry *Entry) *Warning
 
-		// Town.Textには複数書式を持つが、Town.Rubyには複数部分を省略しているケースがある。
-		if len(a1) > 1 && len(a2) == 1 {
-			a3 := make([]string, len(a1))
-			for i := 0; i < len(a1); i++ {
-				a3[i] = a2[0]
+func (f EntryCheckerFunc) Parse(c <-chan interface{}, c1 chan<- interface{}) {
+	defer close(c1)
+	for v := range c {
+		switch v := v.(type) {
+		case error:
+			c1 <- v
+			return
+		case *Warning:
+			c1 <- v
+		case *Entry:
+			if w := f(v); w != nil {
+				c1 <- w
 			}
-			a2 = a3
-		}
-		if len(a1) != len(a2) {
-			// TODO
-		}
-		for i, _ := range a1 {
-			entry1 := new(Entry)
-			*entry1 = *entry
-			entry1.Town = Name{a1[i], a2[i]}
-			c1 <- entry1
+			c1 <- v
 		}
 	}
 }
 
-var parserFilters = []entryParser{
-	entryHandlerFunc(func(entry *Entry) *Entry {
+// noticeHandlersは町域名に含まれる注記を取り除く。
+var noticeHandlers = []EntryHandlerFunc{
+	func(entry *Entry) *Entry {
 		if entry.Town.Text == "以下に掲載がない場合" {
 			entry.Notice = entry.Town.Text
 			entry.Town.Text = ""
 			entry.Town.Ruby = ""
 		}
 		return entry
-	}),
-	entryHandlerFunc(func(entry *Entry) *Entry {
+	},
+	func(entry *Entry) *Entry {
 		const (
 			textSuffix = "（次のビルを除く）"
 			rubySuffix = "(ﾂｷﾞﾉﾋﾞﾙｦﾉｿﾞｸ)"
@@ -120,8 +202,8 @@ var parserFilters = []entryParser{
 			entry.Town.Ruby = entry.Town.Ruby[0 : len(entry.Town.Ruby)-len(rubySuffix)]
 		}
 		return entry
-	}),
-	entryHandlerFunc(func(entry *Entry) *Entry {
+	},
+	func(entry *Entry) *Entry {
 		const (
 			garbText = "（高層棟）"
 			garbRuby = "(ｺｳｿｳﾄｳ)"
@@ -129,8 +211,8 @@ var parserFilters = []entryParser{
 		entry.Town.Text = strings.Replace(entry.Town.Text, garbText, "", -1)
 		entry.Town.Ruby = strings.Replace(entry.Town.Ruby, garbRuby, "", -1)
 		return entry
-	}),
-	entryHandlerFunc(func(entry *Entry) *Entry {
+	},
+	func(entry *Entry) *Entry {
 		const (
 			textSuffix = "の次に番地がくる場合"
 			rubySuffix = "ﾉﾂｷﾞﾆﾊﾞﾝﾁｶﾞｸﾙﾊﾞｱｲ"
@@ -150,8 +232,8 @@ var parserFilters = []entryParser{
 			}
 		}
 		return entry
-	}),
-	entryHandlerFunc(func(entry *Entry) *Entry {
+	},
+	func(entry *Entry) *Entry {
 		const (
 			textSuffix = "一円"
 			rubySuffix = "ｲﾁｴﾝ"
@@ -164,33 +246,218 @@ var parserFilters = []entryParser{
 			}
 		}
 		return entry
-	}),
-	entryHandlerFunc(func(entry *Entry) *Entry {
+	},
+}
+
+// unknownNoticesは、注記を取り除いたあとの町域名に残っていれば疑わしい語句。
+var unknownNotices = []string{"場合", "除く", "以外", "不明"}
+
+// knownFloorsは高層ビルの町域名に含まれる、注記ではない階の表記。
+// "（４７階）"と同じく町域名の一部として残す。
+var knownFloors = []string{"地階・階層不明"}
+
+// checkNoticeは町域名に取り除けなかった注記が残っていれば警告を返す。
+func checkNotice(entry *Entry) *Warning {
+	town := entry.Town.Text
+	for _, s := range knownFloors {
+		town = strings.Replace(town, s, "", -1)
+	}
+	for _, s := range unknownNotices {
+		if strings.Contains(town, s) {
+			return newWarning(entry, WarnUnknownNotice, fmt.Sprintf("town contains %q", s))
+		}
+	}
+	return nil
+}
+
+// expandFilterは町域名の複数書式を展開する。
+type expandFilter struct{}
+
+func (expandFilter) Parse(c <-chan interface{}, c1 chan<- interface{}) {
+	defer close(c1)
+	for v := range c {
+		switch v := v.(type) {
+		case error:
+			c1 <- v
+			return
+		case *Warning:
+			c1 <- v
+		case *Entry:
+			a, warnings := expandTown(v)
+			for _, w := range warnings {
+				c1 <- w
+			}
+			for _, entry := range a {
+				c1 <- entry
+			}
+		}
+	}
+}
+
+// expandTownは町域名の複数書式を展開する。
+// 展開できない場合は警告とともにentryをそのまま返す。
+func expandTown(entry *Entry) ([]*Entry, []*Warning) {
+	var warnings []*Warning
+	town := entry.Town
+	remapRangeVerb(&entry.Town)
+	a1, err := textRule.Eval(entry.Town.Text)
+	if err != nil {
+		entry.Town = town
+		return []*Entry{entry}, append(warnings, newWarning(entry, WarnUnbalancedBrackets, err.Error()))
+	}
+	a2, err := rubyRule.Eval(entry.Town.Ruby)
+	if err != nil {
+		warnings = append(warnings, newWarning(entry, WarnUnbalancedBrackets, err.Error()))
+		a2 = []string{town.Ruby}
+	}
+
+	// Town.Textには複数書式を持つが、Town.Rubyには複数部分を省略しているケースがある。
+	if len(a1) > 1 && len(a2) == 1 {
+		a3 := make([]string, len(a1))
+		for i := 0; i < len(a1); i++ {
+			a3[i] = a2[0]
+		}
+		a2 = a3
+	}
+	if len(a1) != len(a2) {
+		msg := fmt.Sprintf("%d towns but %d rubies", len(a1), len(a2))
+		warnings = append(warnings, newWarning(entry, WarnRubyMismatch, msg))
+		a2 = make([]string, len(a1))
+		for i := range a2 {
+			a2[i] = town.Ruby
+		}
+	}
+	a := make([]*Entry, len(a1))
+	for i := range a1 {
+		entry1 := new(Entry)
+		*entry1 = *entry
+		entry1.Town = Name{a1[i], a2[i]}
+		if strings.Contains(a1[i], "その他") {
+			warnings = append(warnings, newWarning(entry1, WarnSuspiciousOther, "town contains \"その他\""))
+		}
+		a[i] = entry1
+	}
+	return a, warnings
+}
+
+var (
+	// NoticeFilterは町域名から"以下に掲載がない場合"などの注記を取り除く。
+	NoticeFilter Filter = EntryHandlerFunc(func(entry *Entry) *Entry {
+		for _, f := range noticeHandlers {
+			entry = f(entry)
+		}
+		return entry
+	})
+
+	// NoticeCheckFilterはNoticeFilterで取り除けなかった注記を警告する。
+	NoticeCheckFilter Filter = EntryCheckerFunc(checkNotice)
+
+	// PrefectureCheckFilterは都道府県名が全国地方公共団体コードと一致しなければ警告する。
+	PrefectureCheckFilter Filter = EntryCheckerFunc(checkPrefecture)
+
+	// RegionFilterは市区町村名を郡、市町村、区に分けてDistrict、City、Wardにセットする。
+	RegionFilter Filter = EntryHandlerFunc(func(entry *Entry) *Entry {
+		splitRegion(entry)
+		return entry
+	})
+
+	// NormalizeFilterは町域名の英数字や記号をASCII文字に統一する。
+	NormalizeFilter Filter = EntryHandlerFunc(func(entry *Entry) *Entry {
 		entry.Town.Text = normalizeText(entry.Town.Text)
 		return entry
-	}),
-	entryCollectorFunc(func(entry *Entry) bool {
-		open := strings.Count(entry.Town.Text, "(")
-		close := strings.Count(entry.Town.Text, ")")
-		return open == close
-	}),
-	entryExpanderFunc(func(entry *Entry) []*Entry {
-		return []*Entry{entry}
-	}),
+	})
+
+	// KanjiNumeralFilterは町域名の漢数字を算用数字にしてTownKeyにセットする。
+	// Town.Textは変更しない。DefaultFiltersには含まれないので、必要ならExpandFilterより後に追加する。
+	KanjiNumeralFilter Filter = EntryHandlerFunc(func(entry *Entry) *Entry {
+		entry.TownKey = NormalizeKanjiNumerals(entry.Town.Text)
+		return entry
+	})
+
+	// JoinFilterは複数行にまたがる町域名を1つのエントリに連結する。
+	JoinFilter Filter = joinFilter{}
+
+	// ExpandFilterは"町域（ほげ、ふが）"などの複数書式を個別のエントリに展開する。
+	// JoinFilterより後に置かなければならない。
+	// 展開できない場合は警告を送信して、エントリをそのまま送信する。
+	ExpandFilter Filter = expandFilter{}
+)
+
+// DefaultFiltersはParserが標準で使うFilterを新しいスライスで返す。
+// 戻り値に独自のFilterを追加したり、不要なFilterを取り除いたりして
+// Parser.Filtersに設定できる。
+func DefaultFilters() []Filter {
+	return []Filter{
+		NoticeFilter,
+		NoticeCheckFilter,
+		PrefectureCheckFilter,
+		RegionFilter,
+		NormalizeFilter,
+		JoinFilter,
+		ExpandFilter,
+	}
 }
 
 type Parser struct {
+	// Parseで使うFilter。nilならDefaultFiltersを使う。
+	// 空のスライスを設定した場合はKEN_ALL.CSVの行をそのまま返す。
+	Filters []Filter
+
+	// falseなら、KEN_ALL.CSVの書式に合わないレコードがあればエラーとする。
+	// trueなら、修正できるレコードは修正し、できないレコードは読み飛ばして警告する。
+	Lenient bool
+
+	// nilでなければ、処理を継続できる異常が見つかるたびに呼ばれる。
+	Warn func(w *Warning)
+
 	Error error
 }
 
 // Parseは郵便番号データを流すチャネルを返す。
 // エラーが途中で発生した場合、チャネルはclosedになりparser.Errorにエラーをセットする。
 func (parser *Parser) Parse(r io.Reader) <-chan *Entry {
+	filters := parser.Filters
+	if filters == nil {
+		filters = DefaultFilters()
+	}
+	return runFilters(r, filters, parser.Lenient, parser.Warn, &parser.Error)
+}
+
+// RawParserはKEN_ALL.CSVのレコードを加工せずに返す。
+type RawParser struct {
+	// trueなら複数行に分割されたレコードを1つのエントリに連結する。
+	// 連結する以外の加工は行わない。
+	Join bool
+
+	// falseなら、KEN_ALL.CSVの書式に合わないレコードがあればエラーとする。
+	// trueなら、修正できるレコードは修正し、できないレコードは読み飛ばして警告する。
+	Lenient bool
+
+	// nilでなければ、処理を継続できる異常が見つかるたびに呼ばれる。
+	Warn func(w *Warning)
+
+	Error error
+}
+
+// Parseは郵便番号データを流すチャネルを返す。
+// エラーが途中で発生した場合、チャネルはclosedになりparser.Errorにエラーをセットする。
+func (parser *RawParser) Parse(r io.Reader) <-chan *Entry {
+	var filters []Filter
+	if parser.Join {
+		filters = append(filters, JoinFilter)
+	}
+	return runFilters(r, filters, parser.Lenient, parser.Warn, &parser.Error)
+}
+
+// runFiltersはrから読んだエントリをfiltersの順に加工して、結果を流すチャネルを返す。
+// lenientはreadFromCSVLoopに渡す。警告はwarnがnilでなければwarnに渡す。
+// エラーが発生した場合はチャネルをcloseして*errpにエラーをセットする。
+func runFilters(r io.Reader, filters []Filter, lenient bool, warn func(w *Warning), errp *error) <-chan *Entry {
 	c1 := make(chan interface{})
-	go readFromCSVLoop(r, c1)
-	for _, parser := range parserFilters {
+	go readFromCSVLoop(r, c1, lenient)
+	for _, f := range filters {
 		c2 := make(chan interface{})
-		go parser.Parse(c1, c2)
+		go f.Parse(c1, c2)
 		c1 = c2
 	}
 
@@ -198,21 +465,30 @@ func (parser *Parser) Parse(r io.Reader) <-chan *Entry {
 	go func() {
 		defer close(c)
 		for v := range c1 {
-			if err, ok := v.(error); ok {
-				parser.Error = err
+			switch v := v.(type) {
+			case error:
+				*errp = v
 				return
+			case *Warning:
+				if warn != nil {
+					warn(v)
+				}
+			case *Entry:
+				c <- v
 			}
-			c <- v.(*Entry)
 		}
 	}()
 	return c
 }
 
 // rからCSVデータを読み、cにエントリを送信する。
-// エラーが発生した場合はecへエラーを送信する。
-func readFromCSVLoop(r io.Reader, c chan<- interface{}) {
+// エラーが発生した場合はcへエラーを送信する。
+// lenientがtrueなら、不正なレコードは修正または読み飛ばして警告を送信する。
+func readFromCSVLoop(r io.Reader, c chan<- interface{}, lenient bool) {
 	defer close(c)
 	fin := csv.NewReader(r)
+	fin.FieldsPerRecord = -1
+	fin.LazyQuotes = lenient
 	for {
 		record, err := fin.Read()
 		if err == io.EOF {
@@ -222,50 +498,28 @@ func readFromCSVLoop(r io.Reader, c chan<- interface{}) {
 			c <- err
 			return
 		}
+		// フィールドが改行を含む場合があるので、レコードの数ではなく物理的な行番号を使う。
+		line, _ := fin.FieldPos(0)
 
-		isPartialTown, err := strconv.ParseBool(record[9])
-		if err != nil {
-			c <- err
-			return
-		}
-		isLargeTown, err := strconv.ParseBool(record[10])
-		if err != nil {
-			c <- err
-			return
-		}
-		isBlockedScheme, err := strconv.ParseBool(record[11])
-		if err != nil {
-			c <- err
-			return
-		}
-		isOverlappedZip, err := strconv.ParseBool(record[12])
-		if err != nil {
-			c <- err
-			return
-		}
-		status, err := parseStatus(record[13])
-		if err != nil {
-			c <- err
-			return
-		}
-		reason, err := parseReason(record[14])
-		if err != nil {
-			c <- err
-			return
+		p := recordParser{lenient: lenient}
+		entry, rerr := p.Parse(record)
+		if rerr != nil {
+			rerr.Line = line
+			if !lenient {
+				c <- rerr
+				return
+			}
+			c <- &Warning{
+				Kind:    WarnInvalidRecord,
+				Line:    line,
+				Message: rerr.message(),
+			}
+			continue
 		}
-		c <- &Entry{
-			Code:            record[0],
-			OldZip:          record[1],
-			Zip:             record[2],
-			Pref:            Name{record[6], record[3]},
-			Region:          Name{record[7], record[4]},
-			Town:            Name{record[8], record[5]},
-			IsPartialTown:   isPartialTown,
-			IsLargeTown:     isLargeTown,
-			IsBlockedScheme: isBlockedScheme,
-			IsOverlappedZip: isOverlappedZip,
-			Status:          status,
-			Reason:          reason,
+		entry.line = line
+		for _, msg := range p.repairs {
+			c <- newWarning(entry, WarnRepairedRecord, msg)
 		}
+		c <- entry
 	}
 }
@@ -39,7 +39,7 @@ func TestParse(t *testing.T) {
 	expects := []*Entry{
 		&Entry{
 			Code:   "01101",
-			OldZip: "060  ",
+			OldZip: "060",
 			Zip:    "0600000",
 			Pref:   Name{"北海道", "ﾎｯｶｲﾄﾞｳ"},
 			Region: Name{"札幌市中央区", "ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ"},
@@ -47,7 +47,7 @@ func TestParse(t *testing.T) {
 		},
 		&Entry{
 			Code:            "13104",
-			OldZip:          "160  ",
+			OldZip:          "160",
 			Zip:             "1600023",
 			Pref:            Name{"東京都", "ﾄｳｷｮｳﾄ"},
 			Region:          Name{"新宿区", "ｼﾝｼﾞｭｸｸ"},
@@ -56,7 +56,7 @@ func TestParse(t *testing.T) {
 		},
 		&Entry{
 			Code:   "23105",
-			OldZip: "450  ",
+			OldZip: "450",
 			Zip:    "4506247",
 			Pref:   Name{"愛知県", "ｱｲﾁｹﾝ"},
 			Region: Name{"名古屋市中村区", "ﾅｺﾞﾔｼﾅｶﾑﾗｸ"},
@@ -140,7 +140,7 @@ func TestParseSplitted(t *testing.T) {
 
 		&Entry{
 			Code:   "26104",
-			OldZip: "604  ",
+			OldZip: "604",
 			Zip:    "6040983",
 			Pref:   Name{"京都府", "ｷｮｳﾄﾌ"},
 			Region: Name{"京都市中京区", "ｷｮｳﾄｼﾅｶｷﾞｮｳｸ"},
@@ -148,7 +148,7 @@ func TestParseSplitted(t *testing.T) {
 		},
 		&Entry{
 			Code:   "26104",
-			OldZip: "604  ",
+			OldZip: "604",
 			Zip:    "6040983",
 			Pref:   Name{"京都府", "ｷｮｳﾄﾌ"},
 			Region: Name{"京都市中京区", "ｷｮｳﾄｼﾅｶｷﾞｮｳｸ"},
@@ -156,7 +156,7 @@ func TestParseSplitted(t *testing.T) {
 		},
 		&Entry{
 			Code:   "26104",
-			OldZip: "604  ",
+			OldZip: "604",
 			Zip:    "6040983",
 			Pref:   Name{"京都府", "ｷｮｳﾄﾌ"},
 			Region: Name{"京都市中京区", "ｷｮｳﾄｼﾅｶｷﾞｮｳｸ"},
@@ -164,7 +164,7 @@ func TestParseSplitted(t *testing.T) {
 		},
 		&Entry{
 			Code:   "26104",
-			OldZip: "604  ",
+			OldZip: "604",
 			Zip:    "6040983",
 			Pref:   Name{"京都府", "ｷｮｳﾄﾌ"},
 			Region: Name{"京都市中京区", "ｷｮｳﾄｼﾅｶｷﾞｮｳｸ"},
@@ -172,7 +172,7 @@ func TestParseSplitted(t *testing.T) {
 		},
 		&Entry{
 			Code:   "26104",
-			OldZip: "604  ",
+			OldZip: "604",
 			Zip:    "6040983",
 			Pref:   Name{"京都府", "ｷｮｳﾄﾌ"},
 			Region: Name{"京都市中京区", "ｷｮｳﾄｼﾅｶｷﾞｮｳｸ"},
@@ -180,7 +180,7 @@ func TestParseSplitted(t *testing.T) {
 		},
 		&Entry{
 			Code:   "26104",
-			OldZip: "604  ",
+			OldZip: "604",
 			Zip:    "6040983",
 			Pref:   Name{"京都府", "ｷｮｳﾄﾌ"},
 			Region: Name{"京都市中京区", "ｷｮｳﾄｼﾅｶｷﾞｮｳｸ"},
@@ -188,7 +188,7 @@ func TestParseSplitted(t *testing.T) {
 		},
 		&Entry{
 			Code:   "26104",
-			OldZip: "604  ",
+			OldZip: "604",
 			Zip:    "6040983",
 			Pref:   Name{"京都府", "ｷｮｳﾄﾌ"},
 			Region: Name{"京都市中京区", "ｷｮｳﾄｼﾅｶｷﾞｮｳｸ"},
@@ -239,7 +239,7 @@ func TestPraseExpr(t *testing.T) {
 	expects := []*Entry{
 		&Entry{
 			Code:            "01101",
-			OldZip:          "064  ",
+			OldZip:          "064",
 			Zip:             "0640930",
 			Pref:            Name{"北海道", "ﾎｯｶｲﾄﾞｳ"},
 			Region:          Name{"札幌市中央区", "ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ"},
@@ -248,7 +248,7 @@ func TestPraseExpr(t *testing.T) {
 		},
 		&Entry{
 			Code:            "01101",
-			OldZip:          "064  ",
+			OldZip:          "064",
 			Zip:             "0640930",
 			Pref:            Name{"北海道", "ﾎｯｶｲﾄﾞｳ"},
 			Region:          Name{"札幌市中央区", "ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ"},
@@ -257,7 +257,7 @@ func TestPraseExpr(t *testing.T) {
 		},
 		&Entry{
 			Code:            "01101",
-			OldZip:          "064  ",
+			OldZip:          "064",
 			Zip:             "0640930",
 			Pref:            Name{"北海道", "ﾎｯｶｲﾄﾞｳ"},
 			Region:          Name{"札幌市中央区", "ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ"},
@@ -395,7 +395,7 @@ func TestPraseRegionWithNumber(t *testing.T) {
 	expects := []*Entry{
 		&Entry{
 			Code:   "38204",
-			OldZip: "796  ",
+			OldZip: "796",
 			Zip:    "7960088",
 			Pref:   Name{"愛媛県", "ｴﾋﾒｹﾝ"},
 			Region: Name{"八幡浜市", "ﾔﾜﾀﾊﾏｼ"},
@@ -508,3 +508,216 @@ func parseTest(t *testing.T, actuals []string, expects []*Entry, newline string)
 		t.Fatalf("Parse() = %v; Expect not error", parser.Error)
 	}
 }
+
+func TestParseWithFilters(t *testing.T) {
+	actuals := []string{
+		`02206,"03403","0340301","ｱｵﾓﾘｹﾝ","ﾄﾜﾀﾞｼ","ｵｸｾ(ｿﾉﾀ)","青森県","十和田市","奥瀬（その他）",1,1,0,0,0,0`,
+	}
+	var parser Parser
+	parser.Filters = []Filter{
+		NoticeFilter,
+		NormalizeFilter,
+		JoinFilter,
+		EntryHandlerFunc(func(entry *Entry) *Entry {
+			entry.Notice = "custom"
+			return entry
+		}),
+	}
+	c := parser.Parse(strings.NewReader(strings.Join(actuals, "\n")))
+	entry := <-c
+	if entry == nil {
+		t.Fatalf("Parse() = nil; Expect an entry: %v", parser.Error)
+	}
+	if expect := (Name{"奥瀬(その他)", "ｵｸｾ(ｿﾉﾀ)"}); !entry.Town.Equal(expect) {
+		t.Errorf("Parse(): Town = %q; Expect %q", entry.Town, expect)
+	}
+	if entry.Notice != "custom" {
+		t.Errorf("Parse(): Notice = %q; Expect %q", entry.Notice, "custom")
+	}
+	if entry, ok := <-c; ok {
+		t.Errorf("Parse() = %v; Expect end", *entry)
+	}
+}
+
+func TestParseKanjiNumeralFilter(t *testing.T) {
+	actuals := []string{
+		`01101,"064  ","0640930","ﾎｯｶｲﾄﾞｳ","ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ","ﾐﾅﾐ30ｼﾞｮｳﾆｼ(9-11ﾁｮｳﾒ)","北海道","札幌市中央区","南三十条西（９〜１１丁目）",0,0,1,0,0,0`,
+	}
+	var parser Parser
+	parser.Filters = append(DefaultFilters(), KanjiNumeralFilter)
+	var keys []string
+	for entry := range parser.Parse(strings.NewReader(strings.Join(actuals, "\n"))) {
+		if !strings.HasPrefix(entry.Town.Text, "南三十条西") {
+			t.Errorf("Parse(): Town = %q; Expect to keep kanji numerals", entry.Town.Text)
+		}
+		keys = append(keys, entry.TownKey)
+	}
+	if parser.Error != nil {
+		t.Fatalf("Parse() = %v", parser.Error)
+	}
+	expect := []string{"南30条西9丁目", "南30条西10丁目", "南30条西11丁目"}
+	if strings.Join(keys, ",") != strings.Join(expect, ",") {
+		t.Errorf("Parse(): TownKey = %q; Expect %q", keys, expect)
+	}
+}
+
+func TestRawParse(t *testing.T) {
+	actuals := []string{
+		`26104,"604  ","6040983","ｷｮｳﾄﾌ","ｷｮｳﾄｼﾅｶｷﾞｮｳｸ","ｻｻﾔﾁｮｳ","京都府","京都市中京区","笹屋町（麩屋町通竹屋町下る、竹屋",0,0,0,0,0,0`,
+		`26104,"604  ","6040983","ｷｮｳﾄﾌ","ｷｮｳﾄｼﾅｶｷﾞｮｳｸ","ｻｻﾔﾁｮｳ","京都府","京都市中京区","町通麩屋町東入）",0,0,0,0,0,0`,
+		`01101,"060  ","0600000","ﾎｯｶｲﾄﾞｳ","ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ","ｲｶﾆｹｲｻｲｶﾞﾅｲﾊﾞｱｲ","北海道","札幌市中央区","以下に掲載がない場合",0,0,0,0,0,0`,
+	}
+	tests := []struct {
+		join  bool
+		towns []Name
+	}{
+		{
+			join: false,
+			towns: []Name{
+				{"笹屋町（麩屋町通竹屋町下る、竹屋", "ｻｻﾔﾁｮｳ"},
+				{"町通麩屋町東入）", "ｻｻﾔﾁｮｳ"},
+				{"以下に掲載がない場合", "ｲｶﾆｹｲｻｲｶﾞﾅｲﾊﾞｱｲ"},
+			},
+		},
+		{
+			join: true,
+			towns: []Name{
+				{"笹屋町（麩屋町通竹屋町下る、竹屋町通麩屋町東入）", "ｻｻﾔﾁｮｳ"},
+				{"以下に掲載がない場合", "ｲｶﾆｹｲｻｲｶﾞﾅｲﾊﾞｱｲ"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		parser := RawParser{Join: tt.join}
+		c := parser.Parse(strings.NewReader(strings.Join(actuals, "\n")))
+		var towns []Name
+		for entry := range c {
+			towns = append(towns, entry.Town)
+			if entry.Notice != "" {
+				t.Errorf("Parse(Join=%t): Notice = %q; Expect empty", tt.join, entry.Notice)
+			}
+		}
+		if parser.Error != nil {
+			t.Fatalf("Parse(Join=%t) = %v; Expect not error", tt.join, parser.Error)
+		}
+		if len(towns) != len(tt.towns) {
+			t.Fatalf("Parse(Join=%t): %d entries; Expect %d", tt.join, len(towns), len(tt.towns))
+		}
+		for i, town := range towns {
+			if !town.Equal(tt.towns[i]) {
+				t.Errorf("Parse(Join=%t): Town = %q; Expect %q", tt.join, town, tt.towns[i])
+			}
+		}
+	}
+}
+
+func TestParseJoin(t *testing.T) {
+	actuals := []string{
+		`03366,"02955","0295503","ｲﾜﾃｹﾝ","ﾜｶﾞｸﾞﾝﾆｼﾜｶﾞﾏﾁ","ｴｯﾁｭｳﾊﾀ","岩手県","和賀郡西和賀町","越中畑６４地割「岩神の湯、",0,0,0,0,0,0`,
+		`03366,"02955","0295503","ｲﾜﾃｹﾝ","ﾜｶﾞｸﾞﾝﾆｼﾜｶﾞﾏﾁ","ｴｯﾁｭｳﾊﾀ","岩手県","和賀郡西和賀町","湯本」",0,0,0,0,0,0`,
+		`02206,"01855","0185501","ｱｵﾓﾘｹﾝ","ﾄﾜﾀﾞｼ","ｵｸｾ(ｱｵﾌﾞﾅ､","青森県","十和田市","奥瀬（青撫、",1,1,0,0,0,0`,
+		`02206,"03403","0340301","ｱｵﾓﾘｹﾝ","ﾄﾜﾀﾞｼ","ｵｸｾ(ｿﾉﾀ)","青森県","十和田市","奥瀬（その他）",1,1,0,0,0,0`,
+	}
+	expects := []*Entry{
+		&Entry{
+			Code:   "03366",
+			OldZip: "02955",
+			Zip:    "0295503",
+			Pref:   Name{"岩手県", "ｲﾜﾃｹﾝ"},
+			Region: Name{"和賀郡西和賀町", "ﾜｶﾞｸﾞﾝﾆｼﾜｶﾞﾏﾁ"},
+			Town:   Name{"越中畑64地割「岩神の湯、湯本」", "ｴｯﾁｭｳﾊﾀ"},
+		},
+		&Entry{
+			Code:          "02206",
+			OldZip:        "01855",
+			Zip:           "0185501",
+			Pref:          Name{"青森県", "ｱｵﾓﾘｹﾝ"},
+			Region:        Name{"十和田市", "ﾄﾜﾀﾞｼ"},
+			Town:          Name{"奥瀬(青撫、", "ｵｸｾ(ｱｵﾌﾞﾅ､"},
+			IsPartialTown: true,
+			IsLargeTown:   true,
+		},
+		&Entry{
+			Code:          "02206",
+			OldZip:        "03403",
+			Zip:           "0340301",
+			Pref:          Name{"青森県", "ｱｵﾓﾘｹﾝ"},
+			Region:        Name{"十和田市", "ﾄﾜﾀﾞｼ"},
+			Town:          Name{"奥瀬(その他)", "ｵｸｾ(ｿﾉﾀ)"},
+			IsPartialTown: true,
+			IsLargeTown:   true,
+		},
+	}
+	parser := Parser{
+		Filters: []Filter{NoticeFilter, NormalizeFilter, JoinFilter},
+	}
+	var warnings []*Warning
+	parser.Warn = func(w *Warning) {
+		warnings = append(warnings, w)
+	}
+	c := parser.Parse(strings.NewReader(strings.Join(actuals, "\n")))
+	var entries []*Entry
+	for entry := range c {
+		entries = append(entries, entry)
+	}
+	if parser.Error != nil {
+		t.Fatalf("Parse() = %v; Expect not error", parser.Error)
+	}
+	if len(entries) != len(expects) {
+		t.Fatalf("Parse(): %d entries; Expect %d", len(entries), len(expects))
+	}
+	for i, entry := range entries {
+		if entry.Zip != expects[i].Zip {
+			t.Errorf("Parse(): Zip = %q; Expect %q", entry.Zip, expects[i].Zip)
+		}
+		if !entry.Town.Equal(expects[i].Town) {
+			t.Errorf("Parse(): Town = %q; Expect %q", entry.Town, expects[i].Town)
+		}
+	}
+	if len(warnings) != 1 || warnings[0].Entry.Zip != "0185501" {
+		t.Errorf("Parse(): warnings = %v; Expect a warning for 0185501", warnings)
+	}
+}
+
+func TestParseWarnings(t *testing.T) {
+	actuals := []string{
+		`02206,"01855","0185501","ｱｵﾓﾘｹﾝ","ﾄﾜﾀﾞｼ","ｵｸｾ(ｱｵﾌﾞﾅ､","青森県","十和田市","奥瀬（青撫、",1,1,0,0,0,0`,
+		`02206,"03403","0340301","ｱｵﾓﾘｹﾝ","ﾄﾜﾀﾞｼ","ｵｸｾ(ｿﾉﾀ)","青森県","十和田市","奥瀬（その他）",1,1,0,0,0,0`,
+		`13104,"160  ","1600023","ﾄｳｷｮｳﾄ","ｼﾝｼﾞｭｸｸ","ﾆｼｼﾝｼﾞｭｸ(ﾁｶｲ･ｶｲｿｳﾌﾒｲ)","東京都","新宿区","西新宿（地階・階層不明）",0,0,0,0,0,0`,
+		`01101,"064  ","0640941","ﾎｯｶｲﾄﾞｳ","ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ","ｱｻﾋｶﾞｵｶ","北海道","札幌市中央区","旭ケ丘（１、２丁目）",0,0,1,0,0,0`,
+		`01101,"064  ","0640942","ﾎｯｶｲﾄﾞｳ","ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ","ｱｻﾋｶﾞｵｶ","北海道","札幌市中央区","旭ケ丘（番地がない場合）",0,0,1,0,0,0`,
+	}
+	// 3行目の"地階・階層不明"は階の表記なので警告しない。
+	expects := []struct {
+		kind WarningKind
+		line int
+	}{
+		{WarnIncompleteEntry, 1},
+		{WarnUnbalancedBrackets, 1},
+		{WarnUnknownNotice, 5},
+	}
+	var parser Parser
+	var warnings []*Warning
+	parser.Warn = func(w *Warning) {
+		warnings = append(warnings, w)
+	}
+	c := parser.Parse(strings.NewReader(strings.Join(actuals, "\n")))
+	n := 0
+	for range c {
+		n++
+	}
+	if parser.Error != nil {
+		t.Fatalf("Parse() = %v; Expect not error", parser.Error)
+	}
+	if n != 6 {
+		t.Errorf("Parse(): %d entries; Expect %d", n, 6)
+	}
+	if len(warnings) != len(expects) {
+		t.Fatalf("Parse(): warnings = %v; Expect %d warnings", warnings, len(expects))
+	}
+	for i, w := range warnings {
+		if w.Kind != expects[i].kind || w.Line != expects[i].line {
+			t.Errorf("Parse(): warning = %v; Expect %v at line %d", w, expects[i].kind, expects[i].line)
+		}
+	}
+}
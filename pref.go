@@ -0,0 +1,181 @@
+package zipcode
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 地方を表す。
+type Area int
+
+const (
+	AreaHokkaido Area = 1
+	AreaTohoku   Area = 2
+	AreaKanto    Area = 3
+	AreaChubu    Area = 4
+	AreaKinki    Area = 5
+	AreaChugoku  Area = 6
+	AreaShikoku  Area = 7
+	AreaKyushu   Area = 8
+)
+
+var areaNames = map[Area]string{
+	AreaHokkaido: "北海道",
+	AreaTohoku:   "東北",
+	AreaKanto:    "関東",
+	AreaChubu:    "中部",
+	AreaKinki:    "近畿",
+	AreaChugoku:  "中国",
+	AreaShikoku:  "四国",
+	AreaKyushu:   "九州",
+}
+
+// Stringは"関東"のような地方名を返す。
+func (area Area) String() string {
+	if s, ok := areaNames[area]; ok {
+		return s
+	}
+	return fmt.Sprintf("Area(%d)", int(area))
+}
+
+// 都道府県を表す。
+type Prefecture struct {
+	// JIS X 0401の都道府県コード(2桁)。
+	Code string
+
+	// 都道府県名。カナ表記はKEN_ALL.CSVと同じく半角カタカナ。
+	Name Name
+
+	// ローマ字表記。"Tokyo-to"のように区分を含む。
+	Romaji string
+
+	// 英語表記。"Tokyo"のように区分を含まない。
+	English string
+
+	// 地方。
+	Area Area
+}
+
+var prefectures = []Prefecture{
+	{"01", Name{"北海道", "ﾎｯｶｲﾄﾞｳ"}, "Hokkaido", "Hokkaido", AreaHokkaido},
+	{"02", Name{"青森県", "ｱｵﾓﾘｹﾝ"}, "Aomori-ken", "Aomori", AreaTohoku},
+	{"03", Name{"岩手県", "ｲﾜﾃｹﾝ"}, "Iwate-ken", "Iwate", AreaTohoku},
+	{"04", Name{"宮城県", "ﾐﾔｷﾞｹﾝ"}, "Miyagi-ken", "Miyagi", AreaTohoku},
+	{"05", Name{"秋田県", "ｱｷﾀｹﾝ"}, "Akita-ken", "Akita", AreaTohoku},
+	{"06", Name{"山形県", "ﾔﾏｶﾞﾀｹﾝ"}, "Yamagata-ken", "Yamagata", AreaTohoku},
+	{"07", Name{"福島県", "ﾌｸｼﾏｹﾝ"}, "Fukushima-ken", "Fukushima", AreaTohoku},
+	{"08", Name{"茨城県", "ｲﾊﾞﾗｷｹﾝ"}, "Ibaraki-ken", "Ibaraki", AreaKanto},
+	{"09", Name{"栃木県", "ﾄﾁｷﾞｹﾝ"}, "Tochigi-ken", "Tochigi", AreaKanto},
+	{"10", Name{"群馬県", "ｸﾞﾝﾏｹﾝ"}, "Gunma-ken", "Gunma", AreaKanto},
+	{"11", Name{"埼玉県", "ｻｲﾀﾏｹﾝ"}, "Saitama-ken", "Saitama", AreaKanto},
+	{"12", Name{"千葉県", "ﾁﾊﾞｹﾝ"}, "Chiba-ken", "Chiba", AreaKanto},
+	{"13", Name{"東京都", "ﾄｳｷｮｳﾄ"}, "Tokyo-to", "Tokyo", AreaKanto},
+	{"14", Name{"神奈川県", "ｶﾅｶﾞﾜｹﾝ"}, "Kanagawa-ken", "Kanagawa", AreaKanto},
+	{"15", Name{"新潟県", "ﾆｲｶﾞﾀｹﾝ"}, "Niigata-ken", "Niigata", AreaChubu},
+	{"16", Name{"富山県", "ﾄﾔﾏｹﾝ"}, "Toyama-ken", "Toyama", AreaChubu},
+	{"17", Name{"石川県", "ｲｼｶﾜｹﾝ"}, "Ishikawa-ken", "Ishikawa", AreaChubu},
+	{"18", Name{"福井県", "ﾌｸｲｹﾝ"}, "Fukui-ken", "Fukui", AreaChubu},
+	{"19", Name{"山梨県", "ﾔﾏﾅｼｹﾝ"}, "Yamanashi-ken", "Yamanashi", AreaChubu},
+	{"20", Name{"長野県", "ﾅｶﾞﾉｹﾝ"}, "Nagano-ken", "Nagano", AreaChubu},
+	{"21", Name{"岐阜県", "ｷﾞﾌｹﾝ"}, "Gifu-ken", "Gifu", AreaChubu},
+	{"22", Name{"静岡県", "ｼｽﾞｵｶｹﾝ"}, "Shizuoka-ken", "Shizuoka", AreaChubu},
+	{"23", Name{"愛知県", "ｱｲﾁｹﾝ"}, "Aichi-ken", "Aichi", AreaChubu},
+	{"24", Name{"三重県", "ﾐｴｹﾝ"}, "Mie-ken", "Mie", AreaKinki},
+	{"25", Name{"滋賀県", "ｼｶﾞｹﾝ"}, "Shiga-ken", "Shiga", AreaKinki},
+	{"26", Name{"京都府", "ｷｮｳﾄﾌ"}, "Kyoto-fu", "Kyoto", AreaKinki},
+	{"27", Name{"大阪府", "ｵｵｻｶﾌ"}, "Osaka-fu", "Osaka", AreaKinki},
+	{"28", Name{"兵庫県", "ﾋｮｳｺﾞｹﾝ"}, "Hyogo-ken", "Hyogo", AreaKinki},
+	{"29", Name{"奈良県", "ﾅﾗｹﾝ"}, "Nara-ken", "Nara", AreaKinki},
+	{"30", Name{"和歌山県", "ﾜｶﾔﾏｹﾝ"}, "Wakayama-ken", "Wakayama", AreaKinki},
+	{"31", Name{"鳥取県", "ﾄｯﾄﾘｹﾝ"}, "Tottori-ken", "Tottori", AreaChugoku},
+	{"32", Name{"島根県", "ｼﾏﾈｹﾝ"}, "Shimane-ken", "Shimane", AreaChugoku},
+	{"33", Name{"岡山県", "ｵｶﾔﾏｹﾝ"}, "Okayama-ken", "Okayama", AreaChugoku},
+	{"34", Name{"広島県", "ﾋﾛｼﾏｹﾝ"}, "Hiroshima-ken", "Hiroshima", AreaChugoku},
+	{"35", Name{"山口県", "ﾔﾏｸﾞﾁｹﾝ"}, "Yamaguchi-ken", "Yamaguchi", AreaChugoku},
+	{"36", Name{"徳島県", "ﾄｸｼﾏｹﾝ"}, "Tokushima-ken", "Tokushima", AreaShikoku},
+	{"37", Name{"香川県", "ｶｶﾞﾜｹﾝ"}, "Kagawa-ken", "Kagawa", AreaShikoku},
+	{"38", Name{"愛媛県", "ｴﾋﾒｹﾝ"}, "Ehime-ken", "Ehime", AreaShikoku},
+	{"39", Name{"高知県", "ｺｳﾁｹﾝ"}, "Kochi-ken", "Kochi", AreaShikoku},
+	{"40", Name{"福岡県", "ﾌｸｵｶｹﾝ"}, "Fukuoka-ken", "Fukuoka", AreaKyushu},
+	{"41", Name{"佐賀県", "ｻｶﾞｹﾝ"}, "Saga-ken", "Saga", AreaKyushu},
+	{"42", Name{"長崎県", "ﾅｶﾞｻｷｹﾝ"}, "Nagasaki-ken", "Nagasaki", AreaKyushu},
+	{"43", Name{"熊本県", "ｸﾏﾓﾄｹﾝ"}, "Kumamoto-ken", "Kumamoto", AreaKyushu},
+	{"44", Name{"大分県", "ｵｵｲﾀｹﾝ"}, "Oita-ken", "Oita", AreaKyushu},
+	{"45", Name{"宮崎県", "ﾐﾔｻﾞｷｹﾝ"}, "Miyazaki-ken", "Miyazaki", AreaKyushu},
+	{"46", Name{"鹿児島県", "ｶｺﾞｼﾏｹﾝ"}, "Kagoshima-ken", "Kagoshima", AreaKyushu},
+	{"47", Name{"沖縄県", "ｵｷﾅﾜｹﾝ"}, "Okinawa-ken", "Okinawa", AreaKyushu},
+}
+
+// prefectureKeysはLookupPrefectureで受け付ける文字列から都道府県への対応。
+var prefectureKeys = func() map[string]int {
+	m := make(map[string]int)
+	for i, pref := range prefectures {
+		keys := []string{
+			pref.Code,
+			pref.Name.Text,
+			trimPrefectureSuffix(pref.Name.Text),
+			toHiragana(pref.Name.Ruby),
+			strings.ToLower(pref.Romaji),
+			strings.ToLower(pref.English),
+		}
+		for _, key := range keys {
+			m[key] = i
+		}
+	}
+	return m
+}()
+
+// trimPrefectureSuffixは都道府県名から"都"、"府"、"県"を取り除く。
+// 北海道はそのまま返す。
+func trimPrefectureSuffix(s string) string {
+	for _, suffix := range []string{"都", "府", "県"} {
+		if strings.HasSuffix(s, suffix) {
+			return strings.TrimSuffix(s, suffix)
+		}
+	}
+	return s
+}
+
+// Prefecturesは47都道府県をコード順に返す。
+func Prefectures() []Prefecture {
+	a := make([]Prefecture, len(prefectures))
+	copy(a, prefectures)
+	return a
+}
+
+// LookupPrefectureはsに該当する都道府県を返す。
+//
+// sはコード("13")、漢字("東京都"または"東京")、カナ("とうきょうと"、"ﾄｳｷｮｳﾄ"など)、
+// ローマ字("Tokyo-to")、英語("Tokyo")のいずれでもよい。
+func LookupPrefecture(s string) (Prefecture, bool) {
+	s = strings.TrimSpace(s)
+	if i, ok := prefectureKeys[s]; ok {
+		return prefectures[i], true
+	}
+	for _, key := range []string{toHiragana(s), strings.ToLower(s)} {
+		if i, ok := prefectureKeys[key]; ok {
+			return prefectures[i], true
+		}
+	}
+	return Prefecture{}, false
+}
+
+// Prefectureは全国地方公共団体コードの先頭2桁からentryの都道府県を返す。
+func (entry *Entry) Prefecture() (Prefecture, bool) {
+	if len(entry.Code) < 2 {
+		return Prefecture{}, false
+	}
+	return LookupPrefecture(entry.Code[:2])
+}
+
+// checkPrefectureは都道府県名が全国地方公共団体コードと一致しなければ警告を返す。
+func checkPrefecture(entry *Entry) *Warning {
+	pref, ok := entry.Prefecture()
+	if !ok {
+		return newWarning(entry, WarnPrefectureMismatch, fmt.Sprintf("unknown prefecture code %q", entry.Code))
+	}
+	if !pref.Name.Equal(entry.Pref) {
+		return newWarning(entry, WarnPrefectureMismatch, fmt.Sprintf("code %s is %s", pref.Code, pref.Name.Text))
+	}
+	return nil
+}
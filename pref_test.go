@@ -0,0 +1,57 @@
+package zipcode
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPrefectures(t *testing.T) {
+	a := Prefectures()
+	if len(a) != 47 {
+		t.Fatalf("Prefectures(): %d prefectures; Expect 47", len(a))
+	}
+	for i, pref := range a {
+		if code := fmt.Sprintf("%02d", i+1); pref.Code != code {
+			t.Errorf("Prefectures()[%d].Code = %q; Expect %q", i, pref.Code, code)
+		}
+	}
+}
+
+func TestLookupPrefecture(t *testing.T) {
+	tests := []struct {
+		s    string
+		code string
+	}{
+		{"13", "13"},
+		{"東京都", "13"},
+		{"東京", "13"},
+		{"京都", "26"},
+		{"北海道", "01"},
+		{"ﾄｳｷｮｳﾄ", "13"},
+		{"トウキョウト", "13"},
+		{"おおさかふ", "27"},
+		{"Tokyo", "13"},
+		{"osaka-fu", "27"},
+		{"HYOGO", "28"},
+	}
+	for _, tt := range tests {
+		pref, ok := LookupPrefecture(tt.s)
+		if !ok || pref.Code != tt.code {
+			t.Errorf("LookupPrefecture(%q) = %v, %t; Expect %s", tt.s, pref, ok, tt.code)
+		}
+	}
+	if pref, ok := LookupPrefecture("48"); ok {
+		t.Errorf("LookupPrefecture(%q) = %v; Expect not found", "48", pref)
+	}
+}
+
+func TestCheckPrefecture(t *testing.T) {
+	entry := &Entry{Code: "13104", Pref: Name{"東京都", "ﾄｳｷｮｳﾄ"}}
+	if w := checkPrefecture(entry); w != nil {
+		t.Errorf("checkPrefecture(%v) = %v; Expect nil", entry, w)
+	}
+	entry.Code = "14104"
+	if w := checkPrefecture(entry); w == nil || w.Kind != WarnPrefectureMismatch {
+		t.Errorf("checkPrefecture(%v) = %v; Expect %v", entry, w, WarnPrefectureMismatch)
+	}
+}
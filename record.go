@@ -0,0 +1,193 @@
+package zipcode
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// KEN_ALL.CSVの1レコードが持つフィールドの数。
+const numFields = 15
+
+var (
+	// フィールドの数が合わない場合のエラー
+	errFieldCount = fmt.Errorf("wrong number of fields; expect %d", numFields)
+
+	// フラグが0または1でない場合のエラー
+	errFlag = errors.New("flag must be 0 or 1")
+)
+
+// RecordErrorはKEN_ALL.CSVのレコードが書式に合わないことをあらわす。
+type RecordError struct {
+	// レコードが始まる行番号。
+	Line int
+
+	// 不正なフィールドの位置。1から始まる。レコード全体の問題なら0。
+	Column int
+
+	Err error
+}
+
+func (e *RecordError) Error() string {
+	if e.Column == 0 {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+}
+
+// messageは行番号を除いたエラーの内容を返す。
+func (e *RecordError) message() string {
+	if e.Column == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("column %d: %v", e.Column, e.Err)
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// recordParserはKEN_ALL.CSVのレコードをEntryに変換する。
+type recordParser struct {
+	// trueなら修正できるフィールドは修正する。
+	lenient bool
+
+	// 修正したフィールドの説明。
+	repairs []string
+}
+
+// ParseはrecordをEntryに変換する。
+// 返すRecordErrorのLineは呼び出し側でセットしなければならない。
+func (p *recordParser) Parse(record []string) (*Entry, *RecordError) {
+	if len(record) != numFields {
+		if !p.lenient || len(record) < numFields {
+			return nil, &RecordError{Err: errFieldCount}
+		}
+		p.repair(0, fmt.Sprintf("ignore %d extra fields", len(record)-numFields))
+		record = record[:numFields]
+	}
+
+	var entry Entry
+	fields := []struct {
+		i int
+		f func(s string) error
+	}{
+		{0, func(s string) (err error) { entry.Code, err = p.digits(0, s, 5); return }},
+		{1, func(s string) (err error) { entry.OldZip, err = p.oldZip(1, s); return }},
+		{2, func(s string) error {
+			zip, err := p.digits(2, s, 7)
+			entry.Zip = Zip(zip)
+			return err
+		}},
+		{9, func(s string) (err error) { entry.IsPartialTown, err = p.flag(9, s); return }},
+		{10, func(s string) (err error) { entry.IsLargeTown, err = p.flag(10, s); return }},
+		{11, func(s string) (err error) { entry.IsBlockedScheme, err = p.flag(11, s); return }},
+		{12, func(s string) (err error) { entry.IsOverlappedZip, err = p.flag(12, s); return }},
+		{13, func(s string) (err error) { entry.Status, err = parseStatus(p.trim(13, s)); return }},
+		{14, func(s string) (err error) { entry.Reason, err = parseReason(p.trim(14, s)); return }},
+	}
+	for _, field := range fields {
+		if e := field.f(record[field.i]); e != nil {
+			return nil, &RecordError{Column: field.i + 1, Err: e}
+		}
+	}
+	entry.Pref = Name{record[6], record[3]}
+	entry.Region = Name{record[7], record[4]}
+	entry.Town = Name{record[8], record[5]}
+	return &entry, nil
+}
+
+func (p *recordParser) repair(i int, msg string) {
+	if i > 0 {
+		msg = fmt.Sprintf("column %d: %s", i+1, msg)
+	}
+	p.repairs = append(p.repairs, msg)
+}
+
+// trimはlenientなら前後の空白を取り除く。
+func (p *recordParser) trim(i int, s string) string {
+	if !p.lenient {
+		return s
+	}
+	t := strings.TrimSpace(s)
+	if t != s {
+		p.repair(i, fmt.Sprintf("trim %q", s))
+	}
+	return t
+}
+
+// digitsは、sがn桁の数字であればsを返す。
+// lenientなら空白とハイフンを取り除き、先頭の0が欠けていれば補う。
+func (p *recordParser) digits(i int, s string, n int) (string, error) {
+	t := s
+	if p.lenient {
+		t = strings.Map(func(c rune) rune {
+			if c == ' ' || c == '-' {
+				return -1
+			}
+			return c
+		}, t)
+		if isDigits(t) && len(t) > 0 && len(t) < n {
+			t = strings.Repeat("0", n-len(t)) + t
+		}
+	}
+	if len(t) != n || !isDigits(t) {
+		return "", fmt.Errorf("%q is not %d digits", s, n)
+	}
+	if t != s {
+		p.repair(i, fmt.Sprintf("%q to %q", s, t))
+	}
+	return t, nil
+}
+
+// oldZipは、sが3桁の数字と2つの空白、または5桁の数字であれば末尾の空白を除いたsを返す。
+// lenientなら3桁の数字に空白を補う。
+func (p *recordParser) oldZip(i int, s string) (Zip, error) {
+	t := s
+	if p.lenient {
+		t = strings.TrimSpace(t)
+		if len(t) == 3 {
+			t += "  "
+		}
+	}
+	switch {
+	case len(t) == 5 && isDigits(t):
+	case len(t) == 5 && isDigits(t[:3]) && t[3:] == "  ":
+	default:
+		return "", fmt.Errorf("%q is not an old zip code", s)
+	}
+	if t != s {
+		p.repair(i, fmt.Sprintf("%q to %q", s, t))
+	}
+	return Zip(strings.TrimRight(t, " ")), nil
+}
+
+// flagは、sが"0"ならfalse、"1"ならtrueを返す。
+// lenientならstrconv.ParseBoolが受け付ける値も許す。
+func (p *recordParser) flag(i int, s string) (bool, error) {
+	switch s {
+	case "0":
+		return false, nil
+	case "1":
+		return true, nil
+	}
+	if !p.lenient {
+		return false, fmt.Errorf("%q: %w", s, errFlag)
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(s))
+	if err != nil {
+		return false, fmt.Errorf("%q: %w", s, errFlag)
+	}
+	p.repair(i, fmt.Sprintf("%q to %t", s, b))
+	return b, nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package zipcode
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseStrict(t *testing.T) {
+	tests := []struct {
+		record string
+		column int
+	}{
+		{`01101,"060  ","0600000","ﾎｯｶｲﾄﾞｳ"`, 0},
+		{`1101,"060  ","0600000","ﾎｯｶｲﾄﾞｳ","ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ","ｱｻﾋｶﾞｵｶ","北海道","札幌市中央区","旭ケ丘",0,0,1,0,0,0`, 1},
+		{`01101,"060","0600000","ﾎｯｶｲﾄﾞｳ","ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ","ｱｻﾋｶﾞｵｶ","北海道","札幌市中央区","旭ケ丘",0,0,1,0,0,0`, 2},
+		{`01101,"060  ","060-0000","ﾎｯｶｲﾄﾞｳ","ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ","ｱｻﾋｶﾞｵｶ","北海道","札幌市中央区","旭ケ丘",0,0,1,0,0,0`, 3},
+		{`01101,"060  ","0600000","ﾎｯｶｲﾄﾞｳ","ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ","ｱｻﾋｶﾞｵｶ","北海道","札幌市中央区","旭ケ丘",0,0,true,0,0,0`, 12},
+		{`01101,"060  ","0600000","ﾎｯｶｲﾄﾞｳ","ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ","ｱｻﾋｶﾞｵｶ","北海道","札幌市中央区","旭ケ丘",0,0,1,0,3,0`, 14},
+	}
+	for _, tt := range tests {
+		var parser RawParser
+		for range parser.Parse(strings.NewReader(tt.record)) {
+		}
+		var err *RecordError
+		if !errors.As(parser.Error, &err) {
+			t.Errorf("Parse(%s) = %v; Expect RecordError", tt.record, parser.Error)
+			continue
+		}
+		if err.Line != 1 || err.Column != tt.column {
+			t.Errorf("Parse(%s) = %v; Expect line 1, column %d", tt.record, err, tt.column)
+		}
+	}
+}
+
+func TestParseLenient(t *testing.T) {
+	actuals := []string{
+		`1101,"060","060-0000","ﾎｯｶｲﾄﾞｳ","ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ","ｱｻﾋｶﾞｵｶ","北海道","札幌市中央区","旭ケ丘",0,0,true,0,0,0`,
+		`01101,"060  ","0600000","ﾎｯｶｲﾄﾞｳ"`,
+		`01101,"060  ","0600000","ﾎｯｶｲﾄﾞｳ","ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ","ｱｻﾋｶﾞｵｶ","北海道","札幌市中央区","旭ケ丘",0,0,1,0,9,0`,
+	}
+	parser := RawParser{Lenient: true}
+	var warnings []*Warning
+	parser.Warn = func(w *Warning) {
+		warnings = append(warnings, w)
+	}
+	var entries []*Entry
+	for entry := range parser.Parse(strings.NewReader(strings.Join(actuals, "\n"))) {
+		entries = append(entries, entry)
+	}
+	if parser.Error != nil {
+		t.Fatalf("Parse() = %v; Expect not error", parser.Error)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Parse(): %d entries; Expect 1", len(entries))
+	}
+	entry := entries[0]
+	if entry.Code != "01101" || entry.OldZip != "060" || entry.Zip != "0600000" || !entry.IsBlockedScheme {
+		t.Errorf("Parse() = %v; Expect repaired entry", *entry)
+	}
+	kinds := []WarningKind{
+		WarnRepairedRecord,
+		WarnRepairedRecord,
+		WarnRepairedRecord,
+		WarnRepairedRecord,
+		WarnInvalidRecord,
+		WarnInvalidRecord,
+	}
+	if len(warnings) != len(kinds) {
+		t.Fatalf("Parse(): warnings = %v; Expect %d warnings", warnings, len(kinds))
+	}
+	for i, w := range warnings {
+		if w.Kind != kinds[i] {
+			t.Errorf("Parse(): warning = %v; Expect %v", w, kinds[i])
+		}
+	}
+}
+
+func TestParseRecordLine(t *testing.T) {
+	// 1つ目のレコードは町域名に改行を含むので2行にまたがる。
+	s := `01101,"060  ","0600000","ﾎｯｶｲﾄﾞｳ","ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ","ｱｻﾋｶﾞｵｶ","北海道","札幌市中央区","旭ケ` + "\n" + `丘",0,0,1,0,0,0` + "\n" +
+		`01101,"060  ","0600000","ﾎｯｶｲﾄﾞｳ","ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ","ｱｻﾋｶﾞｵｶ","北海道","札幌市中央区","旭ケ丘",0,0,1,0,9,0`
+
+	var parser RawParser
+	for range parser.Parse(strings.NewReader(s)) {
+	}
+	var err *RecordError
+	if !errors.As(parser.Error, &err) {
+		t.Fatalf("Parse() = %v; Expect RecordError", parser.Error)
+	}
+	if err.Line != 3 || err.Column != 14 {
+		t.Errorf("Parse() = %v; Expect line 3, column 14", err)
+	}
+
+	parser = RawParser{Lenient: true}
+	var warnings []*Warning
+	parser.Warn = func(w *Warning) {
+		warnings = append(warnings, w)
+	}
+	for range parser.Parse(strings.NewReader(s)) {
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("Parse(): warnings = %v; Expect 1 warning", warnings)
+	}
+	w := warnings[0]
+	if w.Kind != WarnInvalidRecord || w.Line != 3 {
+		t.Errorf("Parse(): warning = %v; Expect %v at line 3", w, WarnInvalidRecord)
+	}
+	if !strings.HasPrefix(w.Message, "column 14: ") || strings.Contains(w.Message, "line") {
+		t.Errorf("Parse(): warning message = %q; Expect column 14 without line", w.Message)
+	}
+}
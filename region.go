@@ -0,0 +1,76 @@
+package zipcode
+
+import (
+	"strings"
+)
+
+// splitRegionは市区町村名を郡、市町村、区に分けてentryにセットする。
+func splitRegion(entry *Entry) {
+	entry.District = Name{}
+	entry.City = Name{}
+	entry.Ward = Name{}
+	region := entry.Region
+	if len(entry.Code) != 5 || !isDigits(entry.Code) {
+		entry.City = region
+		return
+	}
+	switch municipalityKind(entry.Code, region.Text) {
+	case KindWard:
+		if entry.Code[:2] == "13" {
+			entry.Ward = region
+			return
+		}
+		entry.City, entry.Ward = splitDesignatedCity(entry.Code, region)
+	case KindTown, KindVillage:
+		entry.District, entry.City = splitDistrict(region)
+	default:
+		entry.City = region
+	}
+}
+
+// splitDesignatedCityは"札幌市中央区"を"札幌市"と"中央区"に分ける。
+func splitDesignatedCity(code string, region Name) (city, ward Name) {
+	code, ok := designatedCityCode(code)
+	city = designatedCities[code]
+	if ok && strings.HasPrefix(region.Text, city.Text) && strings.HasPrefix(region.Ruby, city.Ruby) {
+		ward = Name{
+			Text: region.Text[len(city.Text):],
+			Ruby: region.Ruby[len(city.Ruby):],
+		}
+		return city, ward
+	}
+
+	// 表にない政令指定都市は、最初の"市"と"ｼ"で分ける。
+	i := strings.Index(region.Text, "市")
+	j := strings.Index(region.Ruby, "ｼ")
+	if i < 0 || j < 0 {
+		return Name{}, region
+	}
+	i += len("市")
+	j += len("ｼ")
+	city = Name{region.Text[:i], region.Ruby[:j]}
+	ward = Name{region.Text[i:], region.Ruby[j:]}
+	return city, ward
+}
+
+// splitDistrictは"吾川郡いの町"を"吾川郡"と"いの町"に分ける。
+// 郡を持たない場合はdistrictを空にする。
+func splitDistrict(region Name) (district, city Name) {
+	i := strings.Index(region.Text, "郡")
+	if i <= 0 || i+len("郡") == len(region.Text) {
+		return Name{}, region
+	}
+	i += len("郡")
+	district.Text = region.Text[:i]
+	city.Text = region.Text[i:]
+	for _, s := range []string{"ｸﾞﾝ", "ｺﾞｵﾘ"} {
+		if j := strings.Index(region.Ruby, s); j > 0 {
+			j += len(s)
+			district.Ruby = region.Ruby[:j]
+			city.Ruby = region.Ruby[j:]
+			return district, city
+		}
+	}
+	city.Ruby = region.Ruby
+	return district, city
+}
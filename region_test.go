@@ -0,0 +1,80 @@
+package zipcode
+
+import (
+	"testing"
+)
+
+func TestSplitRegion(t *testing.T) {
+	tests := []struct {
+		code     string
+		region   Name
+		district Name
+		city     Name
+		ward     Name
+	}{
+		{
+			code:   "01101",
+			region: Name{"札幌市中央区", "ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ"},
+			city:   Name{"札幌市", "ｻｯﾎﾟﾛｼ"},
+			ward:   Name{"中央区", "ﾁｭｳｵｳｸ"},
+		},
+		{
+			code:   "22101",
+			region: Name{"静岡市葵区", "ｼｽﾞｵｶｼｱｵｲｸ"},
+			city:   Name{"静岡市", "ｼｽﾞｵｶｼ"},
+			ward:   Name{"葵区", "ｱｵｲｸ"},
+		},
+		{
+			code:   "01110",
+			region: Name{"札幌市清田区", "ｻｯﾎﾟﾛｼｷﾖﾀｸ"},
+			city:   Name{"札幌市", "ｻｯﾎﾟﾛｼ"},
+			ward:   Name{"清田区", "ｷﾖﾀｸ"},
+		},
+		{
+			code:   "14110",
+			region: Name{"横浜市戸塚区", "ﾖｺﾊﾏｼﾄﾂｶｸ"},
+			city:   Name{"横浜市", "ﾖｺﾊﾏｼ"},
+			ward:   Name{"戸塚区", "ﾄﾂｶｸ"},
+		},
+		{
+			code:   "27128",
+			region: Name{"大阪市中央区", "ｵｵｻｶｼﾁｭｳｵｳｸ"},
+			city:   Name{"大阪市", "ｵｵｻｶｼ"},
+			ward:   Name{"中央区", "ﾁｭｳｵｳｸ"},
+		},
+		{
+			code:   "13104",
+			region: Name{"新宿区", "ｼﾝｼﾞｭｸｸ"},
+			ward:   Name{"新宿区", "ｼﾝｼﾞｭｸｸ"},
+		},
+		{
+			code:   "07203",
+			region: Name{"郡山市", "ｺｵﾘﾔﾏｼ"},
+			city:   Name{"郡山市", "ｺｵﾘﾔﾏｼ"},
+		},
+		{
+			code:     "39386",
+			region:   Name{"吾川郡いの町", "ｱｶﾞﾜｸﾞﾝｲﾉﾁｮｳ"},
+			district: Name{"吾川郡", "ｱｶﾞﾜｸﾞﾝ"},
+			city:     Name{"いの町", "ｲﾉﾁｮｳ"},
+		},
+		{
+			code:   "13381",
+			region: Name{"三宅島三宅村", "ﾐﾔｹｼﾞﾏﾐﾔｹﾑﾗ"},
+			city:   Name{"三宅島三宅村", "ﾐﾔｹｼﾞﾏﾐﾔｹﾑﾗ"},
+		},
+	}
+	for _, tt := range tests {
+		entry := &Entry{Code: tt.code, Region: tt.region}
+		splitRegion(entry)
+		if !entry.District.Equal(tt.district) {
+			t.Errorf("splitRegion(%q): District = %q; Expect %q", tt.region.Text, entry.District, tt.district)
+		}
+		if !entry.City.Equal(tt.city) {
+			t.Errorf("splitRegion(%q): City = %q; Expect %q", tt.region.Text, entry.City, tt.city)
+		}
+		if !entry.Ward.Equal(tt.ward) {
+			t.Errorf("splitRegion(%q): Ward = %q; Expect %q", tt.region.Text, entry.Ward, tt.ward)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package zipcode
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ローマ字での長音の書き方を表す。
+type LongVowel int
+
+const (
+	// 長音を書かない。"ﾄｳｷｮｳ"は"tokyo"になる。
+	LongVowelOmit LongVowel = 0
+
+	// 長音をマクロンで書く。"ﾄｳｷｮｳ"は"tōkyō"になる。
+	LongVowelMacron LongVowel = 1
+
+	// 長音をカナのとおりに書く。"ﾄｳｷｮｳ"は"toukyou"になる。
+	LongVowelSpelled LongVowel = 2
+)
+
+// romajiTableはカタカナとヘボン式ローマ字の対応。
+var romajiTable = map[string]string{
+	"ア": "a", "イ": "i", "ウ": "u", "エ": "e", "オ": "o",
+	"カ": "ka", "キ": "ki", "ク": "ku", "ケ": "ke", "コ": "ko",
+	"サ": "sa", "シ": "shi", "ス": "su", "セ": "se", "ソ": "so",
+	"タ": "ta", "チ": "chi", "ツ": "tsu", "テ": "te", "ト": "to",
+	"ナ": "na", "ニ": "ni", "ヌ": "nu", "ネ": "ne", "ノ": "no",
+	"ハ": "ha", "ヒ": "hi", "フ": "fu", "ヘ": "he", "ホ": "ho",
+	"マ": "ma", "ミ": "mi", "ム": "mu", "メ": "me", "モ": "mo",
+	"ヤ": "ya", "ユ": "yu", "ヨ": "yo",
+	"ラ": "ra", "リ": "ri", "ル": "ru", "レ": "re", "ロ": "ro",
+	"ワ": "wa", "ヰ": "i", "ヱ": "e", "ヲ": "o", "ン": "n",
+	"ガ": "ga", "ギ": "gi", "グ": "gu", "ゲ": "ge", "ゴ": "go",
+	"ザ": "za", "ジ": "ji", "ズ": "zu", "ゼ": "ze", "ゾ": "zo",
+	"ダ": "da", "ヂ": "ji", "ヅ": "zu", "デ": "de", "ド": "do",
+	"バ": "ba", "ビ": "bi", "ブ": "bu", "ベ": "be", "ボ": "bo",
+	"パ": "pa", "ピ": "pi", "プ": "pu", "ペ": "pe", "ポ": "po",
+	"ヴ": "vu",
+	"ァ": "a", "ィ": "i", "ゥ": "u", "ェ": "e", "ォ": "o",
+	"ャ": "ya", "ュ": "yu", "ョ": "yo", "ヮ": "wa", "ヵ": "ka", "ヶ": "ke",
+
+	"ファ": "fa", "フィ": "fi", "フェ": "fe", "フォ": "fo",
+	"ティ": "ti", "ディ": "di", "トゥ": "tu", "ドゥ": "du",
+	"ウィ": "wi", "ウェ": "we", "ウォ": "wo",
+	"ヴァ": "va", "ヴィ": "vi", "ヴェ": "ve", "ヴォ": "vo",
+	"シェ": "she", "ジェ": "je", "チェ": "che", "ツァ": "tsa",
+}
+
+func init() {
+	// "キャ"などの拗音を追加する。
+	for _, c := range "キギシジチヂニヒビピミリ" {
+		s := romajiTable[string(c)]
+		s = s[:len(s)-1]
+		if s != "sh" && s != "ch" && s != "j" {
+			s += "y"
+		}
+		for _, y := range "ャュョ" {
+			romajiTable[string(c)+string(y)] = s + romajiTable[string(y)][1:]
+		}
+	}
+}
+
+// macronsは母音とマクロン付きの母音の対応。
+var macrons = map[rune]rune{
+	'a': 'ā', 'i': 'ī', 'u': 'ū', 'e': 'ē', 'o': 'ō',
+}
+
+func isVowel(c rune) bool {
+	_, ok := macrons[c]
+	return ok
+}
+
+// Romanizeは半角または全角のカタカナsをヘボン式のローマ字にする。
+// 長音の書き方はvで選ぶ。カナ以外の文字はそのまま残す。
+func Romanize(s string, v LongVowel) string {
+	r := []rune(toKatakana(s))
+	var (
+		buf    []rune
+		sokuon bool // 直前が"ッ"
+		afterN bool // 直前が"ン"
+	)
+	// longは直前の母音を伸ばす。spellは長音をカナのとおりに書く場合の母音。
+	long := func(spell rune) {
+		switch v {
+		case LongVowelMacron:
+			buf[len(buf)-1] = macrons[buf[len(buf)-1]]
+		case LongVowelSpelled:
+			buf = append(buf, spell)
+		}
+	}
+	for i := 0; i < len(r); i++ {
+		switch r[i] {
+		case 'ッ':
+			sokuon = true
+			continue
+		case 'ー':
+			if len(buf) > 0 && isVowel(buf[len(buf)-1]) {
+				long(buf[len(buf)-1])
+			}
+			continue
+		}
+		var syl string
+		if i+1 < len(r) {
+			if s, ok := romajiTable[string(r[i:i+2])]; ok {
+				syl = s
+				i++
+			}
+		}
+		if syl == "" {
+			s, ok := romajiTable[string(r[i])]
+			if !ok {
+				buf = append(buf, r[i])
+				sokuon, afterN = false, false
+				continue
+			}
+			syl = s
+		}
+
+		last := rune(0)
+		if len(buf) > 0 {
+			last = buf[len(buf)-1]
+		}
+		switch {
+		case syl == "u" && (last == 'o' || last == 'u'),
+			syl == "o" && last == 'o':
+			long(rune(syl[0]))
+			continue
+		case afterN && (isVowel(rune(syl[0])) || syl[0] == 'y'):
+			// "ｼﾝｵｵｸﾎﾞ"の"n'o"のように、"ン"の後に母音やヤ行が続く場合は区切る。
+			buf = append(buf, '\'')
+		}
+		afterN = syl == "n"
+		if sokuon {
+			if strings.HasPrefix(syl, "ch") {
+				buf = append(buf, 't')
+			} else if !isVowel(rune(syl[0])) {
+				buf = append(buf, rune(syl[0]))
+			}
+			sokuon = false
+		}
+		buf = append(buf, []rune(syl)...)
+	}
+	return string(buf)
+}
+
+// Romajiはカナ表記の名前をヘボン式のローマ字にして、先頭の文字を大文字にする。
+func (name Name) Romaji(v LongVowel) string {
+	return capitalize(Romanize(name.Ruby, v))
+}
+
+func capitalize(s string) string {
+	c, n := utf8.DecodeRuneInString(s)
+	if n == 0 {
+		return s
+	}
+	return string(unicode.ToUpper(c)) + s[n:]
+}
+
+// designationsは市区町村名の末尾にある行政区画の名前と、そのカナ表記。
+var designations = []struct {
+	text string
+	ruby string
+}{
+	{"区", "ｸ"},
+	{"市", "ｼ"},
+	{"郡", "ｸﾞﾝ"},
+	{"郡", "ｺﾞｵﾘ"},
+	{"町", "ﾁｮｳ"},
+	{"町", "ﾏﾁ"},
+	{"村", "ﾑﾗ"},
+	{"村", "ｿﾝ"},
+}
+
+// romajiRegionは"新宿区"を"Shinjuku-ku"のように、行政区画の名前をハイフンで区切ったローマ字にする。
+// suffixがfalseなら"Shinjuku"のように行政区画の名前を省く。
+func romajiRegion(name Name, v LongVowel, suffix bool) string {
+	for _, d := range designations {
+		if strings.HasSuffix(name.Text, d.text) && strings.HasSuffix(name.Ruby, d.ruby) && len(name.Ruby) > len(d.ruby) {
+			stem := Name{
+				Text: strings.TrimSuffix(name.Text, d.text),
+				Ruby: strings.TrimSuffix(name.Ruby, d.ruby),
+			}
+			if !suffix {
+				return stem.Romaji(v)
+			}
+			return stem.Romaji(v) + "-" + Romanize(d.ruby, v)
+		}
+	}
+	return name.Romaji(v)
+}
+
+// romajiPrefは都道府県名を"Tokyo"のように、都、府、県を除いたローマ字にする。
+func romajiPref(name Name, v LongVowel) string {
+	for _, d := range []struct{ text, ruby string }{{"都", "ﾄ"}, {"府", "ﾌ"}, {"県", "ｹﾝ"}} {
+		if strings.HasSuffix(name.Text, d.text) && strings.HasSuffix(name.Ruby, d.ruby) {
+			name.Ruby = strings.TrimSuffix(name.Ruby, d.ruby)
+			break
+		}
+	}
+	return name.Romaji(v)
+}
+
+// RomajiAddressは"Nishishinjuku, Shinjuku-ku, Tokyo"のように、
+// 町域、区、市町村、郡、都道府県の順に並べたローマ字の住所を返す。
+func (entry *Entry) RomajiAddress(v LongVowel) string {
+	var a []string
+	if entry.Town.Ruby != "" {
+		a = append(a, entry.Town.Romaji(v))
+	}
+	a = append(a, entry.romajiRegions(v, true)...)
+	if entry.Pref.Ruby != "" {
+		a = append(a, romajiPref(entry.Pref, v))
+	}
+	return strings.Join(a, ", ")
+}
+
+// romajiRegionsは区、市町村、郡の順に市区町村名をローマ字にする。
+func (entry *Entry) romajiRegions(v LongVowel, suffix bool) []string {
+	regions := []Name{entry.Ward, entry.City, entry.District}
+	if entry.Ward.Text == "" && entry.City.Text == "" && entry.District.Text == "" {
+		regions = []Name{entry.Region}
+	}
+	var a []string
+	for _, name := range regions {
+		if name.Ruby != "" {
+			a = append(a, romajiRegion(name, v, suffix))
+		}
+	}
+	return a
+}
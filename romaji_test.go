@@ -0,0 +1,72 @@
+package zipcode
+
+import (
+	"testing"
+)
+
+func TestRomanize(t *testing.T) {
+	tests := []struct {
+		s      string
+		v      LongVowel
+		expect string
+	}{
+		{"ﾄｳｷｮｳ", LongVowelOmit, "tokyo"},
+		{"ﾄｳｷｮｳ", LongVowelMacron, "tōkyō"},
+		{"ﾄｳｷｮｳ", LongVowelSpelled, "toukyou"},
+		{"ｵｵｻｶ", LongVowelMacron, "ōsaka"},
+		{"ｼﾝｼﾞｭｸ", LongVowelOmit, "shinjuku"},
+		{"ﾎｯｶｲﾄﾞｳ", LongVowelOmit, "hokkaido"},
+		{"ﾊｯﾁｮｳﾎﾞﾘ", LongVowelMacron, "hatchōbori"},
+		{"ｼﾝｵｵｸﾎﾞ", LongVowelOmit, "shin'okubo"},
+		{"ｼﾝｼﾞｭｸﾊﾟｰｸﾀﾜｰ", LongVowelMacron, "shinjukupākutawā"},
+		{"ｼﾝｼﾞｭｸﾊﾟｰｸﾀﾜｰ", LongVowelOmit, "shinjukupakutawa"},
+		{"ﾐﾅﾐ30ｼﾞｮｳﾆｼ", LongVowelOmit, "minami30jonishi"},
+		{"トウキョウ", LongVowelOmit, "tokyo"},
+	}
+	for _, tt := range tests {
+		if s := Romanize(tt.s, tt.v); s != tt.expect {
+			t.Errorf("Romanize(%q, %d) = %q; Expect %q", tt.s, tt.v, s, tt.expect)
+		}
+	}
+}
+
+func TestRomajiAddress(t *testing.T) {
+	idx := testIndex(t)
+	tests := []struct {
+		zip    string
+		v      LongVowel
+		expect string
+	}{
+		{"1600023", LongVowelOmit, "Nishishinjuku, Shinjuku-ku, Tokyo"},
+		{"1600000", LongVowelOmit, "Shinjuku-ku, Tokyo"},
+		{"0640941", LongVowelMacron, "Asahigaoka, Chūō-ku, Sapporo-shi, Hokkaidō"},
+		{"4500002", LongVowelOmit, "Meieki, Nakamura-ku, Nagoya-shi, Aichi"},
+	}
+	for _, tt := range tests {
+		a := idx.Lookup(tt.zip)
+		if len(a) != 1 {
+			t.Fatalf("Lookup(%q) = %v", tt.zip, a)
+		}
+		if s := a[0].RomajiAddress(tt.v); s != tt.expect {
+			t.Errorf("RomajiAddress(%d) = %q; Expect %q", tt.v, s, tt.expect)
+		}
+	}
+	ward := &Entry{
+		Code:   "01110",
+		Pref:   Name{"北海道", "ﾎｯｶｲﾄﾞｳ"},
+		Region: Name{"札幌市清田区", "ｻｯﾎﾟﾛｼｷﾖﾀｸ"},
+	}
+	splitRegion(ward)
+	if s, expect := ward.RomajiAddress(LongVowelOmit), "Kiyota-ku, Sapporo-shi, Hokkaido"; s != expect {
+		t.Errorf("RomajiAddress() = %q; Expect %q", s, expect)
+	}
+	entry := &Entry{
+		Pref:     Name{"高知県", "ｺｳﾁｹﾝ"},
+		Region:   Name{"吾川郡いの町", "ｱｶﾞﾜｸﾞﾝｲﾉﾁｮｳ"},
+		District: Name{"吾川郡", "ｱｶﾞﾜｸﾞﾝ"},
+		City:     Name{"いの町", "ｲﾉﾁｮｳ"},
+	}
+	if s, expect := entry.RomajiAddress(LongVowelOmit), "Ino-cho, Agawa-gun, Kochi"; s != expect {
+		t.Errorf("RomajiAddress() = %q; Expect %q", s, expect)
+	}
+}
@@ -0,0 +1,270 @@
+package zipcode
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// スナップショットの形式
+//
+//	magic    [4]byte "ZCSN"
+//	version  uvarint
+//	nstrings uvarint
+//	strings  [nstrings]{len uvarint, [len]byte}
+//	nentries uvarint
+//	entries  [nentries]entry
+//	crc32    [4]byte 先頭からentriesまでのCRC-32(IEEE)をビッグエンディアンで
+//
+// entryはCodeとZipを数値としてuvarintで、名前などは文字列表の添字をuvarintで持つ。
+// District、City、WardはRegionから求められるため持たない。
+//
+//	code, oldZip, zip, pref.Text, pref.Ruby, region.Text, region.Ruby,
+//	town.Text, town.Ruby, townKey, notice, flags, status, reason
+//
+// バージョン1はtownKeyを持たない。
+const (
+	snapshotMagic   = "ZCSN"
+	snapshotVersion = 2
+)
+
+const (
+	snapshotPartialTown = 1 << iota
+	snapshotLargeTown
+	snapshotBlockedScheme
+	snapshotOverlappedZip
+)
+
+var (
+	// スナップショットの形式が正しくない場合のエラー
+	errSnapshotFormat = errors.New("invalid snapshot format")
+
+	// スナップショットのチェックサムが一致しない場合のエラー
+	errSnapshotChecksum = errors.New("snapshot checksum mismatch")
+)
+
+// IsSnapshotは、bがスナップショットの先頭であればtrueを返す。
+// bは4バイトあれば判定できる。
+func IsSnapshot(b []byte) bool {
+	return bytes.HasPrefix(b, []byte(snapshotMagic))
+}
+
+// WriteSnapshotはentriesを読み込みの速いバイナリ形式でwへ書き込む。
+// 同じ文字列は1度だけ書き込む。
+func WriteSnapshot(w io.Writer, entries []*Entry) error {
+	var (
+		strs   []string
+		strIdx = make(map[string]uint64)
+		body   []byte
+		buf    [binary.MaxVarintLen64]byte
+	)
+	putUvarint := func(v uint64) {
+		n := binary.PutUvarint(buf[:], v)
+		body = append(body, buf[:n]...)
+	}
+	putString := func(s string) {
+		i, ok := strIdx[s]
+		if !ok {
+			i = uint64(len(strs))
+			strIdx[s] = i
+			strs = append(strs, s)
+		}
+		putUvarint(i)
+	}
+	putNumber := func(s string, n int) error {
+		v, err := strconv.ParseUint(s, 10, 64)
+		if err != nil || len(s) != n {
+			return fmt.Errorf("%q is not %d digits", s, n)
+		}
+		putUvarint(v)
+		return nil
+	}
+
+	putUvarint(uint64(len(entries)))
+	for _, entry := range entries {
+		if err := putNumber(entry.Code, 5); err != nil {
+			return err
+		}
+		putString(string(entry.OldZip))
+		if err := putNumber(string(entry.Zip), 7); err != nil {
+			return err
+		}
+		putString(entry.Pref.Text)
+		putString(entry.Pref.Ruby)
+		putString(entry.Region.Text)
+		putString(entry.Region.Ruby)
+		putString(entry.Town.Text)
+		putString(entry.Town.Ruby)
+		putString(entry.TownKey)
+		putString(entry.Notice)
+		var flags byte
+		if entry.IsPartialTown {
+			flags |= snapshotPartialTown
+		}
+		if entry.IsLargeTown {
+			flags |= snapshotLargeTown
+		}
+		if entry.IsBlockedScheme {
+			flags |= snapshotBlockedScheme
+		}
+		if entry.IsOverlappedZip {
+			flags |= snapshotOverlappedZip
+		}
+		body = append(body, flags, byte(entry.Status), byte(entry.Reason))
+	}
+
+	h := crc32.NewIEEE()
+	fout := bufio.NewWriter(io.MultiWriter(w, h))
+	fout.WriteString(snapshotMagic)
+	n := binary.PutUvarint(buf[:], snapshotVersion)
+	fout.Write(buf[:n])
+	n = binary.PutUvarint(buf[:], uint64(len(strs)))
+	fout.Write(buf[:n])
+	for _, s := range strs {
+		n = binary.PutUvarint(buf[:], uint64(len(s)))
+		fout.Write(buf[:n])
+		fout.WriteString(s)
+	}
+	fout.Write(body)
+	if err := fout.Flush(); err != nil {
+		return err
+	}
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], h.Sum32())
+	_, err := w.Write(sum[:])
+	return err
+}
+
+// snapshotDecoderはスナップショットのバイト列を先頭から読む。
+type snapshotDecoder struct {
+	b    []byte
+	strs []string
+	err  error
+}
+
+func (d *snapshotDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		d.err = errSnapshotFormat
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *snapshotDecoder) byte() byte {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.b) == 0 {
+		d.err = errSnapshotFormat
+		return 0
+	}
+	c := d.b[0]
+	d.b = d.b[1:]
+	return c
+}
+
+func (d *snapshotDecoder) bytes(n uint64) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if uint64(len(d.b)) < n {
+		d.err = errSnapshotFormat
+		return nil
+	}
+	p := d.b[:n]
+	d.b = d.b[n:]
+	return p
+}
+
+func (d *snapshotDecoder) string() string {
+	i := d.uvarint()
+	if d.err != nil {
+		return ""
+	}
+	if i >= uint64(len(d.strs)) {
+		d.err = errSnapshotFormat
+		return ""
+	}
+	return d.strs[i]
+}
+
+func (d *snapshotDecoder) number(n int) string {
+	v := d.uvarint()
+	return fmt.Sprintf("%0*d", n, v)
+}
+
+// LoadSnapshotはWriteSnapshotで書き込んだデータをrから読み込む。
+func LoadSnapshot(r io.Reader) ([]*Entry, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if !IsSnapshot(b) || len(b) < len(snapshotMagic)+4 {
+		return nil, errSnapshotFormat
+	}
+	body := b[:len(b)-4]
+	if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(b[len(b)-4:]) {
+		return nil, errSnapshotChecksum
+	}
+
+	d := &snapshotDecoder{b: body[len(snapshotMagic):]}
+	version := d.uvarint()
+	if d.err == nil && (version < 1 || version > snapshotVersion) {
+		return nil, fmt.Errorf("unsupported snapshot version %d", version)
+	}
+	n := d.uvarint()
+	if n > uint64(len(d.b)) {
+		return nil, errSnapshotFormat
+	}
+	d.strs = make([]string, 0, n)
+	for i := uint64(0); i < n && d.err == nil; i++ {
+		d.strs = append(d.strs, string(d.bytes(d.uvarint())))
+	}
+	n = d.uvarint()
+	if n > uint64(len(d.b)) {
+		return nil, errSnapshotFormat
+	}
+	entries := make([]*Entry, 0, n)
+	for i := uint64(0); i < n && d.err == nil; i++ {
+		var entry Entry
+		entry.Code = d.number(5)
+		// 古いスナップショットの旧郵便番号は末尾に空白を持つ。
+		entry.OldZip = Zip(strings.TrimRight(d.string(), " "))
+		entry.Zip = Zip(d.number(7))
+		entry.Pref = Name{d.string(), d.string()}
+		entry.Region = Name{d.string(), d.string()}
+		entry.Town = Name{d.string(), d.string()}
+		if version >= 2 {
+			entry.TownKey = d.string()
+		}
+		entry.Notice = d.string()
+		flags := d.byte()
+		entry.IsPartialTown = flags&snapshotPartialTown != 0
+		entry.IsLargeTown = flags&snapshotLargeTown != 0
+		entry.IsBlockedScheme = flags&snapshotBlockedScheme != 0
+		entry.IsOverlappedZip = flags&snapshotOverlappedZip != 0
+		entry.Status = Status(d.byte())
+		entry.Reason = Reason(d.byte())
+		splitRegion(&entry)
+		entries = append(entries, &entry)
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	if len(d.b) != 0 {
+		return nil, errSnapshotFormat
+	}
+	return entries, nil
+}
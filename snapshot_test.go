@@ -0,0 +1,112 @@
+package zipcode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"strings"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	idx := testIndex(t)
+	var buf bytes.Buffer
+	if err := WriteSnapshot(&buf, idx.Entries()); err != nil {
+		t.Fatalf("WriteSnapshot() = %v", err)
+	}
+	b := buf.Bytes()
+	if !IsSnapshot(b) {
+		t.Errorf("IsSnapshot(%q) = false; Expect true", b[:4])
+	}
+	entries, err := LoadSnapshot(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("LoadSnapshot() = %v", err)
+	}
+	if len(entries) != len(idx.Entries()) {
+		t.Fatalf("LoadSnapshot(): %d entries; Expect %d", len(entries), len(idx.Entries()))
+	}
+	for i, entry := range entries {
+		expect := *idx.Entries()[i]
+		expect.line = 0
+		if *entry != expect {
+			t.Errorf("LoadSnapshot() = %v; Expect %v", *entry, expect)
+		}
+	}
+
+	b[len(b)/2] ^= 0xff
+	if _, err := LoadSnapshot(bytes.NewReader(b)); err != errSnapshotChecksum {
+		t.Errorf("LoadSnapshot(broken) = %v; Expect %v", err, errSnapshotChecksum)
+	}
+}
+
+func TestSnapshotTownKey(t *testing.T) {
+	data := `01101,"064  ","0640930","ﾎｯｶｲﾄﾞｳ","ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ","ﾐﾅﾐ30ｼﾞｮｳﾆｼ(9-11ﾁｮｳﾒ)","北海道","札幌市中央区","南三十条西（９〜１１丁目）",0,0,1,0,0,0`
+	var parser Parser
+	parser.Filters = append(DefaultFilters(), KanjiNumeralFilter)
+	var entries []*Entry
+	for entry := range parser.Parse(strings.NewReader(data)) {
+		entries = append(entries, entry)
+	}
+	if parser.Error != nil {
+		t.Fatalf("Parse() = %v", parser.Error)
+	}
+	var buf bytes.Buffer
+	if err := WriteSnapshot(&buf, entries); err != nil {
+		t.Fatalf("WriteSnapshot() = %v", err)
+	}
+	loaded, err := LoadSnapshot(&buf)
+	if err != nil {
+		t.Fatalf("LoadSnapshot() = %v", err)
+	}
+	if len(loaded) != len(entries) {
+		t.Fatalf("LoadSnapshot(): %d entries; Expect %d", len(loaded), len(entries))
+	}
+	for i, entry := range loaded {
+		if entry.TownKey == "" || entry.TownKey != entries[i].TownKey {
+			t.Errorf("LoadSnapshot(): TownKey = %q; Expect %q", entry.TownKey, entries[i].TownKey)
+		}
+	}
+}
+
+func TestLoadSnapshotVersion1(t *testing.T) {
+	// バージョン1のスナップショットはtownKeyを持たない。
+	var (
+		b   []byte
+		buf [binary.MaxVarintLen64]byte
+	)
+	put := func(v uint64) {
+		n := binary.PutUvarint(buf[:], v)
+		b = append(b, buf[:n]...)
+	}
+	b = append(b, snapshotMagic...)
+	put(1)
+	strs := []string{"064", "北海道", "ﾎｯｶｲﾄﾞｳ", "札幌市中央区", "ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ", "旭ケ丘", "ｱｻﾋｶﾞｵｶ", ""}
+	put(uint64(len(strs)))
+	for _, s := range strs {
+		put(uint64(len(s)))
+		b = append(b, s...)
+	}
+	put(1)
+	put(1101)
+	put(0)
+	put(640941)
+	for i := 1; i < len(strs); i++ {
+		put(uint64(i))
+	}
+	b = append(b, snapshotBlockedScheme, 0, 0)
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc32.ChecksumIEEE(b))
+	b = append(b, sum[:]...)
+
+	entries, err := LoadSnapshot(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("LoadSnapshot() = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("LoadSnapshot(): %d entries; Expect 1", len(entries))
+	}
+	entry := entries[0]
+	if entry.Zip != "0640941" || entry.Town.Text != "旭ケ丘" || entry.TownKey != "" || entry.Notice != "" || !entry.IsBlockedScheme {
+		t.Errorf("LoadSnapshot() = %v; Expect 0640941 旭ケ丘", *entry)
+	}
+}
@@ -0,0 +1,320 @@
+package zipcode
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SQLの方言を表す。
+type Dialect int
+
+const (
+	// PostgreSQL。
+	DialectPostgres Dialect = 1
+
+	// MySQL。
+	DialectMySQL Dialect = 2
+
+	// SQLite。
+	DialectSQLite Dialect = 3
+)
+
+var dialectNames = map[string]Dialect{
+	"postgres": DialectPostgres,
+	"mysql":    DialectMySQL,
+	"sqlite":   DialectSQLite,
+}
+
+// ParseDialectは"postgres"、"mysql"、"sqlite"のいずれかをDialectに変換する。
+func ParseDialect(s string) (Dialect, error) {
+	if d, ok := dialectNames[s]; ok {
+		return d, nil
+	}
+	return 0, fmt.Errorf("unknown SQL dialect: %s", s)
+}
+
+// 1つのINSERT文にまとめる既定の行数。
+const defaultBatchSize = 500
+
+// SQLWriterは郵便番号データを正規化したテーブルの定義とデータをSQL文で出力する。
+//
+// テーブルはprefectures、municipalities、towns、zip_codesの4つで、
+// 町域は市区町村と名前の組ごとに1行となる。
+//
+// Beginでテーブル定義を出力したあと、WriteEntryでエントリを1つずつ渡して、
+// 最後にCommitを呼ぶ。行はBatchSizeごとに出力するので、すべてのエントリを保持しない。
+type SQLWriter struct {
+	Dialect Dialect
+
+	// 1つのINSERT文にまとめる行数。0なら500行。
+	BatchSize int
+
+	// trueなら、PostgreSQLではINSERTの代わりにCOPYを使う。
+	UseCopy bool
+
+	w       *bufio.Writer
+	tables  []*sqlTable
+	seen    map[string]bool
+	townIDs map[string]int
+}
+
+// sqlTableは出力するテーブルの定義と、まだ出力していない行。
+type sqlTable struct {
+	name    string
+	columns []sqlColumn
+	rows    [][]interface{}
+}
+
+type sqlColumn struct {
+	name string
+	typ  string
+}
+
+// Beginはテーブル定義とトランザクションの開始をwへ出力する。
+func (sw *SQLWriter) Begin(w io.Writer) error {
+	switch sw.Dialect {
+	case DialectPostgres, DialectMySQL, DialectSQLite:
+	default:
+		return fmt.Errorf("unknown SQL dialect: %d", sw.Dialect)
+	}
+	sw.w = bufio.NewWriter(w)
+	sw.tables = newSQLTables()
+	sw.seen = make(map[string]bool)
+	sw.townIDs = make(map[string]int)
+	for _, t := range sw.tables {
+		sw.writeCreateTable(sw.w, t)
+	}
+	fmt.Fprintln(sw.w, "BEGIN;")
+	return nil
+}
+
+// WriteEntryはentryを正規化したテーブルの行に加える。
+// どれかのテーブルの行がBatchSizeに達したら、外部キーで参照される順にすべてのテーブルの行を出力する。
+func (sw *SQLWriter) WriteEntry(entry *Entry) error {
+	if sw.w == nil {
+		return errors.New("SQLWriter: WriteEntry called before Begin")
+	}
+	prefs, cities, towns, zips := sw.tables[0], sw.tables[1], sw.tables[2], sw.tables[3]
+	pref := entry.Code[:2]
+	if !sw.seen[pref] {
+		sw.seen[pref] = true
+		prefs.rows = append(prefs.rows, []interface{}{pref, entry.Pref.Text, entry.Pref.Ruby})
+	}
+	if !sw.seen[entry.Code] {
+		sw.seen[entry.Code] = true
+		cities.rows = append(cities.rows, []interface{}{entry.Code, pref, entry.Region.Text, entry.Region.Ruby})
+	}
+	key := entry.Code + "\x00" + entry.Town.Text + "\x00" + entry.Town.Ruby
+	id, ok := sw.townIDs[key]
+	if !ok {
+		id = len(sw.townIDs) + 1
+		sw.townIDs[key] = id
+		towns.rows = append(towns.rows, []interface{}{
+			id, entry.Code, entry.Town.Text, entry.Town.Ruby,
+			entry.IsLargeTown, entry.IsBlockedScheme, entry.Notice,
+		})
+	}
+	key = fmt.Sprintf("%s\x00%d", entry.Zip, id)
+	if !sw.seen[key] {
+		sw.seen[key] = true
+		zips.rows = append(zips.rows, []interface{}{
+			string(entry.Zip), string(entry.OldZip), id,
+			entry.IsPartialTown, entry.IsOverlappedZip,
+		})
+	}
+	for _, t := range sw.tables {
+		if len(t.rows) >= sw.batchSize() {
+			sw.flush()
+			break
+		}
+	}
+	return nil
+}
+
+// Commitは残りの行、トランザクションの終了、インデックスを出力する。
+// Commitを呼ばなければトランザクションは終了しないので、
+// エントリの読み込みに失敗した場合は呼ばずに捨てればよい。
+func (sw *SQLWriter) Commit() error {
+	if sw.w == nil {
+		return errors.New("SQLWriter: Commit called before Begin")
+	}
+	sw.flush()
+	fmt.Fprintln(sw.w, "COMMIT;")
+	fmt.Fprintln(sw.w, "CREATE INDEX municipalities_prefecture_code ON municipalities (prefecture_code);")
+	fmt.Fprintln(sw.w, "CREATE INDEX towns_municipality_code ON towns (municipality_code);")
+	fmt.Fprintln(sw.w, "CREATE INDEX zip_codes_town_id ON zip_codes (town_id);")
+	return sw.w.Flush()
+}
+
+func (sw *SQLWriter) batchSize() int {
+	if sw.BatchSize <= 0 {
+		return defaultBatchSize
+	}
+	return sw.BatchSize
+}
+
+// flushはまだ出力していない行をテーブルの順に出力する。
+func (sw *SQLWriter) flush() {
+	for _, t := range sw.tables {
+		if len(t.rows) == 0 {
+			continue
+		}
+		if sw.Dialect == DialectPostgres && sw.UseCopy {
+			sw.writeCopy(sw.w, t)
+		} else {
+			sw.writeInsert(sw.w, t)
+		}
+		t.rows = t.rows[:0]
+	}
+}
+
+// newSQLTablesは外部キーで参照される順にテーブルの定義を返す。
+// MySQLは列定義のREFERENCESを無視するので、外部キーはテーブル制約で書く。
+func newSQLTables() []*sqlTable {
+	prefs := &sqlTable{
+		name: "prefectures",
+		columns: []sqlColumn{
+			{"code", "CHAR(2) PRIMARY KEY"},
+			{"name", "VARCHAR(8) NOT NULL"},
+			{"kana", "VARCHAR(16) NOT NULL"},
+		},
+	}
+	cities := &sqlTable{
+		name: "municipalities",
+		columns: []sqlColumn{
+			{"code", "CHAR(5) PRIMARY KEY"},
+			{"prefecture_code", "CHAR(2) NOT NULL"},
+			{"name", "VARCHAR(64) NOT NULL"},
+			{"kana", "VARCHAR(128) NOT NULL"},
+			{"", "FOREIGN KEY (prefecture_code) REFERENCES prefectures (code)"},
+		},
+	}
+	towns := &sqlTable{
+		name: "towns",
+		columns: []sqlColumn{
+			{"id", "INTEGER PRIMARY KEY"},
+			{"municipality_code", "CHAR(5) NOT NULL"},
+			{"name", "VARCHAR(255) NOT NULL"},
+			{"kana", "VARCHAR(255) NOT NULL"},
+			{"is_large_town", "BOOLEAN NOT NULL"},
+			{"is_blocked_scheme", "BOOLEAN NOT NULL"},
+			{"notice", "VARCHAR(255) NOT NULL"},
+			{"", "FOREIGN KEY (municipality_code) REFERENCES municipalities (code)"},
+		},
+	}
+	zips := &sqlTable{
+		name: "zip_codes",
+		columns: []sqlColumn{
+			{"zip", "CHAR(7) NOT NULL"},
+			{"old_zip", "CHAR(5) NOT NULL"},
+			{"town_id", "INTEGER NOT NULL"},
+			{"is_partial_town", "BOOLEAN NOT NULL"},
+			{"is_overlapped_zip", "BOOLEAN NOT NULL"},
+			{"", "PRIMARY KEY (zip, town_id)"},
+			{"", "FOREIGN KEY (town_id) REFERENCES towns (id)"},
+		},
+	}
+	return []*sqlTable{prefs, cities, towns, zips}
+}
+
+func (sw *SQLWriter) writeCreateTable(w *bufio.Writer, t *sqlTable) {
+	fmt.Fprintf(w, "CREATE TABLE %s (\n", t.name)
+	for i, c := range t.columns {
+		sep := ","
+		if i == len(t.columns)-1 {
+			sep = ""
+		}
+		if c.name == "" {
+			fmt.Fprintf(w, "\t%s%s\n", c.typ, sep)
+		} else {
+			fmt.Fprintf(w, "\t%s %s%s\n", c.name, c.typ, sep)
+		}
+	}
+	fmt.Fprintln(w, ");")
+}
+
+// columnNamesはテーブル制約を除いた列名をカンマ区切りで返す。
+func (t *sqlTable) columnNames() string {
+	var a []string
+	for _, c := range t.columns {
+		if c.name != "" {
+			a = append(a, c.name)
+		}
+	}
+	return strings.Join(a, ", ")
+}
+
+// writeInsertはテーブルの行を1つのINSERT文で出力する。
+func (sw *SQLWriter) writeInsert(w *bufio.Writer, t *sqlTable) {
+	fmt.Fprintf(w, "INSERT INTO %s (%s) VALUES\n", t.name, t.columnNames())
+	for i, row := range t.rows {
+		if i > 0 {
+			w.WriteString(",\n")
+		}
+		w.WriteString("\t(")
+		for j, v := range row {
+			if j > 0 {
+				w.WriteString(", ")
+			}
+			w.WriteString(sw.literal(v))
+		}
+		w.WriteString(")")
+	}
+	w.WriteString(";\n")
+}
+
+// literalはvをSQLのリテラルに変換する。
+func (sw *SQLWriter) literal(v interface{}) string {
+	switch v := v.(type) {
+	case bool:
+		switch {
+		case sw.Dialect == DialectPostgres && v:
+			return "TRUE"
+		case sw.Dialect == DialectPostgres:
+			return "FALSE"
+		case v:
+			return "1"
+		default:
+			return "0"
+		}
+	case string:
+		s := strings.Replace(v, "'", "''", -1)
+		if sw.Dialect == DialectMySQL {
+			s = strings.Replace(s, `\`, `\\`, -1)
+		}
+		return "'" + s + "'"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+var copyEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// writeCopyはPostgreSQLのCOPY文でテーブルの行を出力する。
+func (sw *SQLWriter) writeCopy(w *bufio.Writer, t *sqlTable) {
+	fmt.Fprintf(w, "COPY %s (%s) FROM stdin;\n", t.name, t.columnNames())
+	for _, row := range t.rows {
+		for j, v := range row {
+			if j > 0 {
+				w.WriteByte('\t')
+			}
+			switch v := v.(type) {
+			case bool:
+				if v {
+					w.WriteByte('t')
+				} else {
+					w.WriteByte('f')
+				}
+			case string:
+				copyEscaper.WriteString(w, v)
+			default:
+				fmt.Fprint(w, v)
+			}
+		}
+		w.WriteByte('\n')
+	}
+	w.WriteString("\\.\n")
+}
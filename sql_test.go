@@ -0,0 +1,115 @@
+package zipcode
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSQLWriter(t *testing.T) {
+	idx := testIndex(t)
+	tests := []struct {
+		w      SQLWriter
+		expect []string
+	}{
+		{
+			w: SQLWriter{Dialect: DialectPostgres},
+			expect: []string{
+				"CREATE TABLE zip_codes (\n",
+				"\t('13', '東京都', 'ﾄｳｷｮｳﾄ'),\n",
+				"\t(2, '13104', '西新宿', 'ﾆｼｼﾝｼﾞｭｸ', FALSE, TRUE, ''),\n",
+				"\t('1600023', '160', 2, FALSE, FALSE),\n",
+				"COMMIT;\nCREATE INDEX",
+			},
+		},
+		{
+			w: SQLWriter{Dialect: DialectSQLite, BatchSize: 2},
+			expect: []string{
+				"INSERT INTO towns (id, municipality_code, name, kana, is_large_town, is_blocked_scheme, notice) VALUES\n" +
+					"\t(1, '13104', '', '', 0, 0, '以下に掲載がない場合'),\n" +
+					"\t(2, '13104', '西新宿', 'ﾆｼｼﾝｼﾞｭｸ', 0, 1, '');\n" +
+					"INSERT INTO zip_codes",
+			},
+		},
+		{
+			w: SQLWriter{Dialect: DialectMySQL},
+			expect: []string{
+				"\tprefecture_code CHAR(2) NOT NULL,\n",
+				"\tFOREIGN KEY (prefecture_code) REFERENCES prefectures (code)\n);\n",
+				"\tFOREIGN KEY (municipality_code) REFERENCES municipalities (code)\n);\n",
+				"\tPRIMARY KEY (zip, town_id),\n\tFOREIGN KEY (town_id) REFERENCES towns (id)\n);\n",
+			},
+		},
+		{
+			w: SQLWriter{Dialect: DialectPostgres, UseCopy: true},
+			expect: []string{
+				"COPY zip_codes (zip, old_zip, town_id, is_partial_town, is_overlapped_zip) FROM stdin;\n" +
+					"1600000\t160\t1\tf\tf\n",
+				"\\.\nCOMMIT;\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := writeSQL(&tt.w, &buf, idx.Entries()); err != nil {
+			t.Fatalf("Write(%v) = %v", tt.w, err)
+		}
+		for _, s := range tt.expect {
+			if !strings.Contains(buf.String(), s) {
+				t.Errorf("Write(%v): output does not contain %q", tt.w, s)
+			}
+		}
+	}
+}
+
+func writeSQL(sw *SQLWriter, w io.Writer, entries []*Entry) error {
+	if err := sw.Begin(w); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := sw.WriteEntry(entry); err != nil {
+			return err
+		}
+	}
+	return sw.Commit()
+}
+
+func TestSQLWriterStream(t *testing.T) {
+	idx := testIndex(t)
+	var buf bytes.Buffer
+	sw := SQLWriter{Dialect: DialectSQLite, BatchSize: 2}
+	if err := sw.Begin(&buf); err != nil {
+		t.Fatalf("Begin() = %v", err)
+	}
+	entries := idx.Entries()
+	for _, entry := range entries[:2] {
+		if err := sw.WriteEntry(entry); err != nil {
+			t.Fatalf("WriteEntry() = %v", err)
+		}
+	}
+	sw.w.Flush()
+	// 2行目で町域がBatchSizeに達するので、参照される順にすべてのテーブルを出力する。
+	s := buf.String()
+	for _, name := range []string{"prefectures", "municipalities", "towns", "zip_codes"} {
+		if !strings.Contains(s, "INSERT INTO "+name+" ") {
+			t.Errorf("WriteEntry(): output does not contain INSERT INTO %s", name)
+		}
+	}
+	if strings.Contains(s, "COMMIT;") {
+		t.Errorf("WriteEntry(): output contains COMMIT before Commit")
+	}
+	if err := (&SQLWriter{Dialect: DialectSQLite}).WriteEntry(entries[0]); err == nil {
+		t.Errorf("WriteEntry() before Begin = nil; Expect an error")
+	}
+	if err := (&SQLWriter{}).Begin(&buf); err == nil {
+		t.Errorf("Begin() with no dialect = nil; Expect an error")
+	}
+}
+
+func TestSQLLiteral(t *testing.T) {
+	w := SQLWriter{Dialect: DialectMySQL}
+	if s := w.literal(`a'b\c`); s != `'a''b\\c'` {
+		t.Errorf("literal(%q) = %s; Expect %s", `a'b\c`, s, `'a''b\\c'`)
+	}
+}
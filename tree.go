@@ -0,0 +1,206 @@
+package zipcode
+
+import (
+	"sort"
+)
+
+// 住所の階層を表す。
+type Level int
+
+const (
+	// 都道府県。
+	LevelPrefecture Level = 1
+
+	// 市区町村。
+	LevelMunicipality Level = 2
+
+	// 町域。
+	LevelTown Level = 3
+)
+
+// Treeは都道府県、市区町村、町域の階層で住所を補完するための索引。
+type Tree struct {
+	root  Node
+	nodes map[string]*Node
+}
+
+// Nodeは住所の階層の1つを表す。
+type Node struct {
+	Level Level
+
+	// 都道府県なら2桁、市区町村なら5桁のコード。町域では空。
+	Code string
+
+	// 名前。市区町村は"札幌市中央区"のように郡や市を含む。
+	Name Name
+
+	// この住所に該当するエントリ。
+	// 市区町村では、町域名を持たないエントリだけを含む。都道府県では空。
+	Entries []*Entry
+
+	// 上の階層。都道府県ではnil。
+	Parent *Node
+
+	children []*Node
+	towns    map[string]*Node
+	keys     *trie
+}
+
+// NewTreeはentriesからTreeを作成する。
+// 子はentriesに現れた順に並ぶ。
+func NewTree(entries []*Entry) *Tree {
+	t := &Tree{nodes: make(map[string]*Node)}
+	for _, entry := range entries {
+		if len(entry.Code) < 2 {
+			continue
+		}
+		pref := t.child(&t.root, LevelPrefecture, entry.Code[:2], entry.Pref)
+		// "札幌市中央区"は"中央区"でも、"吾川郡いの町"は"いの町"でも補完する。
+		city := t.child(pref, LevelMunicipality, entry.Code, entry.Region,
+			Name{entry.City.Text + entry.Ward.Text, entry.City.Ruby + entry.Ward.Ruby}, entry.Ward)
+		if entry.Town.Text == "" {
+			city.Entries = append(city.Entries, entry)
+			continue
+		}
+		town, ok := city.towns[entry.Town.Text]
+		if !ok {
+			town = &Node{Level: LevelTown, Name: entry.Town, Parent: city}
+			if city.towns == nil {
+				city.towns = make(map[string]*Node)
+			}
+			city.towns[entry.Town.Text] = town
+			city.addChild(town, entry.Town)
+		}
+		town.Entries = append(town.Entries, entry)
+	}
+	sort.SliceStable(t.root.children, func(i, j int) bool {
+		return t.root.children[i].Code < t.root.children[j].Code
+	})
+	for _, pref := range t.root.children {
+		sort.SliceStable(pref.children, func(i, j int) bool {
+			return pref.children[i].Code < pref.children[j].Code
+		})
+	}
+	return t
+}
+
+// childはparentの子のうちcodeを持つものを返す。なければ作成する。
+// 子はnameの他にkeysでも補完できる。
+func (t *Tree) child(parent *Node, level Level, code string, name Name, keys ...Name) *Node {
+	if n, ok := t.nodes[code]; ok {
+		return n
+	}
+	n := &Node{Level: level, Code: code, Name: name}
+	if parent != &t.root {
+		n.Parent = parent
+	}
+	t.nodes[code] = n
+	parent.addChild(n, append([]Name{name}, keys...)...)
+	return n
+}
+
+func (n *Node) addChild(child *Node, keys ...Name) {
+	n.children = append(n.children, child)
+	if n.keys == nil {
+		n.keys = new(trie)
+	}
+	for _, key := range keys {
+		n.keys.insert(foldVariants(key.Text), child)
+		n.keys.insert(foldVariants(key.Ruby), child)
+	}
+}
+
+// Prefecturesはすべての都道府県をコードの順に返す。
+func (t *Tree) Prefectures() []*Node {
+	return t.root.children
+}
+
+// Nodeはコードが2桁なら都道府県、5桁なら市区町村を返す。
+func (t *Tree) Node(code string) (*Node, bool) {
+	n, ok := t.nodes[code]
+	return n, ok
+}
+
+// Completeは名前または読みがprefixで始まる都道府県を返す。
+func (t *Tree) Complete(prefix string) []*Node {
+	return t.root.Complete(prefix)
+}
+
+// Childrenは1つ下の階層をすべて返す。
+// 都道府県なら市区町村をコードの順に、市区町村なら町域をエントリの順に返す。
+func (n *Node) Children() []*Node {
+	return n.children
+}
+
+// Completeは1つ下の階層のうち、名前または読みがprefixで始まるものを返す。
+// 読みはひらがなとカタカナのどちらでもよい。
+func (n *Node) Complete(prefix string) []*Node {
+	if n.keys == nil {
+		return nil
+	}
+	t := n.keys.find(foldVariants(prefix))
+	if t == nil {
+		return nil
+	}
+	found := make(map[*Node]bool)
+	t.walk(func(child *Node) {
+		found[child] = true
+	})
+	var a []*Node
+	for _, child := range n.children {
+		if found[child] {
+			a = append(a, child)
+		}
+	}
+	return a
+}
+
+// Zipsはこの住所に該当する郵便番号を重複なく昇順で返す。
+func (n *Node) Zips() []Zip {
+	return zipCodes(n.Entries)
+}
+
+// trieは文字ごとに分岐して、キーの前方一致でNodeを探す。
+type trie struct {
+	next  map[rune]*trie
+	nodes []*Node
+}
+
+func (t *trie) insert(key string, n *Node) {
+	if key == "" {
+		return
+	}
+	for _, c := range key {
+		t1, ok := t.next[c]
+		if !ok {
+			if t.next == nil {
+				t.next = make(map[rune]*trie)
+			}
+			t1 = new(trie)
+			t.next[c] = t1
+		}
+		t = t1
+	}
+	t.nodes = append(t.nodes, n)
+}
+
+// findはprefixまで辿った部分木を返す。なければnilを返す。
+func (t *trie) find(prefix string) *trie {
+	for _, c := range prefix {
+		t = t.next[c]
+		if t == nil {
+			return nil
+		}
+	}
+	return t
+}
+
+// walkはtとその部分木にあるすべてのNodeをfに渡す。
+func (t *trie) walk(f func(n *Node)) {
+	for _, n := range t.nodes {
+		f(n)
+	}
+	for _, t1 := range t.next {
+		t1.walk(f)
+	}
+}
@@ -0,0 +1,70 @@
+package zipcode
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func nodeNames(a []*Node) string {
+	var names []string
+	for _, n := range a {
+		names = append(names, n.Name.Text)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestTree(t *testing.T) {
+	tree := NewTree(testIndex(t).Entries())
+	if s := nodeNames(tree.Prefectures()); s != "北海道,東京都,愛知県" {
+		t.Errorf("Prefectures() = %s; Expect 北海道,東京都,愛知県", s)
+	}
+	pref, ok := tree.Node("13")
+	if !ok || pref.Level != LevelPrefecture {
+		t.Fatalf("Node(%q) = %v, %t; Expect a prefecture", "13", pref, ok)
+	}
+	city := pref.Children()[0]
+	if city.Code != "13104" || city.Level != LevelMunicipality || city.Parent != pref {
+		t.Errorf("Children() = %v; Expect 13104", city)
+	}
+	if s := fmt.Sprint(city.Zips()); s != "[1600000]" {
+		t.Errorf("Zips() = %s; Expect 1600000", s)
+	}
+	if s := nodeNames(city.Children()); s != "西新宿,西新宿新宿パークタワー地階・階層不明,市谷田町" {
+		t.Errorf("Children() = %s", s)
+	}
+	town := city.Children()[0]
+	if town.Level != LevelTown || fmt.Sprint(town.Zips()) != "[1600023]" {
+		t.Errorf("Zips() = %v; Expect 1600023", town.Zips())
+	}
+}
+
+func TestTreeComplete(t *testing.T) {
+	tree := NewTree(testIndex(t).Entries())
+	tests := []struct {
+		prefix []string
+		expect string
+	}{
+		{[]string{"東"}, "東京都"},
+		{[]string{"とう"}, "東京都"},
+		{[]string{""}, "北海道,東京都,愛知県"},
+		{[]string{"北海道", "ちゅう"}, "札幌市中央区"},
+		{[]string{"北海道", "札幌"}, "札幌市中央区"},
+		{[]string{"東京都", "新宿区", "にし"}, "西新宿,西新宿新宿パークタワー地階・階層不明"},
+		{[]string{"東京都", "新宿区", "市谷"}, "市谷田町"},
+		{[]string{"東京都", "新宿区", "歌舞伎町"}, ""},
+		{[]string{"北海道", "札幌市中央区", "旭が"}, "旭ケ丘"},
+	}
+	for _, tt := range tests {
+		a := tree.Complete(tt.prefix[0])
+		for _, prefix := range tt.prefix[1:] {
+			if len(a) != 1 {
+				break
+			}
+			a = a[0].Complete(prefix)
+		}
+		if s := nodeNames(a); s != tt.expect {
+			t.Errorf("Complete(%q) = %s; Expect %s", tt.prefix, s, tt.expect)
+		}
+	}
+}
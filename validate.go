@@ -0,0 +1,205 @@
+package zipcode
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Validationは郵便番号と住所の整合性を検査した結果。
+type Validation struct {
+	// 住所が郵便番号の範囲に含まれる。
+	Valid bool
+
+	// 住所を解析した結果。
+	Address *Address
+
+	// 住所を範囲に含むエントリ。郵便番号が一致しないものも含む。
+	// 番地が書かれていないなどで範囲を絞り込めない場合は、候補となるすべてのエントリを含む。
+	Entries []*Entry
+
+	// Validがfalseの場合に、住所を範囲に含む郵便番号。
+	Suggestions []Zip
+}
+
+// Validateは住所addrが郵便番号zipの範囲に含まれるかどうかを検査する。
+//
+// 町域が複数の郵便番号を持つ場合は、丁目や番地まで比べる。
+// どの町域にも該当しない住所は、その市区町村の"以下に掲載がない場合"の郵便番号に含まれる。
+// 1つの郵便番号が複数の町域をあらわす場合は、どの町域でも範囲に含まれる。
+func (idx *Index) Validate(zip, addr string) (*Validation, error) {
+	z, err := ParseZip(zip)
+	if err != nil {
+		return nil, err
+	}
+	if z.IsOld() {
+		return nil, fmt.Errorf("%q: %w", zip, errInvalidZip)
+	}
+	a, err := idx.ParseAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	v := &Validation{
+		Address: a,
+		Entries: idx.coveringEntries(normalizeAddressInput(addr)),
+	}
+	for _, entry := range v.Entries {
+		if entry.Zip == z {
+			v.Valid = true
+			break
+		}
+	}
+	if !v.Valid {
+		v.Suggestions = zipCodes(v.Entries)
+	}
+	return v, nil
+}
+
+// 住所と町域名の一致の度合い。
+const (
+	coverCatchAll = iota + 1 // 以下に掲載がない場合
+	coverPartial             // 町域名は一致するが番地を絞り込めない
+	coverTown                // 町域名または番地まで一致
+)
+
+// coveringEntriesは正規化された住所sを範囲に含むエントリを返す。
+func (idx *Index) coveringEntries(s string) []*Entry {
+	g := idx.gazetteer()
+	candidates, t := g.findRegions(s)
+	key := streetKey(NormalizeKanjiNumerals(t))
+
+	var (
+		a     []*Entry
+		level int
+		n     int
+	)
+	for _, r := range candidates {
+		entries, l, m := coverTownKey(g.towns[r.entry.Code], key)
+		if l < level || l == level && m < n {
+			continue
+		}
+		if l > level || m > n {
+			a = a[:0]
+			level, n = l, m
+		}
+		a = append(a, entries...)
+	}
+	return a
+}
+
+// coverTownKeyはentriesのうち住所のキーkeyを範囲に含むものと、一致の度合い、一致した長さを返す。
+func coverTownKey(entries []*Entry, key string) ([]*Entry, int, int) {
+	var (
+		a []*Entry
+		n int
+	)
+	for _, entry := range entries {
+		k := townKey(entry)
+		if k == "" || !strings.HasPrefix(key, k) || len(k) < n {
+			continue
+		}
+		if endsWithDigit(k) && len(key) > len(k) && isDigit(rune(key[len(k)])) {
+			continue
+		}
+		if len(k) > n {
+			a = a[:0]
+			n = len(k)
+		}
+		a = append(a, entry)
+	}
+	if n > 0 {
+		return a, coverTown, n
+	}
+
+	// "町域1番地"のように番地ごとに分かれた町域のどれにも一致しない。
+	// 町域のキーから末尾の番号を除いた部分のうち、住所のキーの先頭に最も長く一致するものを探す。
+	for _, entry := range entries {
+		k := townKey(entry)
+		town := strings.TrimRightFunc(k, isDigit)
+		if town == "" || town == k || len(town) < n || !strings.HasPrefix(key, town) {
+			continue
+		}
+		if len(key) > len(town) && !isDigit(rune(key[len(town)])) {
+			continue
+		}
+		if len(town) > n {
+			a = a[:0]
+			n = len(town)
+		}
+		a = append(a, entry)
+	}
+	if n > 0 {
+		return a, coverPartial, n
+	}
+
+	for _, entry := range entries {
+		if entry.Town.Text == "" {
+			a = append(a, entry)
+		}
+	}
+	if len(a) == 0 {
+		return nil, 0, 0
+	}
+	return a, coverCatchAll, 0
+}
+
+// townKeyはentryの町域名をstreetKeyで照合できる形にする。
+func townKey(entry *Entry) string {
+	s := entry.TownKey
+	if s == "" {
+		s = NormalizeKanjiNumerals(entry.Town.Text)
+	}
+	return streetKey(normalizeAddressInput(s))
+}
+
+// streetKeysは数字の後に続く丁目、番地、号をあらわす文字列。
+var streetKeys = []string{"丁目", "番地", "番", "号", "の"}
+
+// streetKeyは住所sの数字の後に続く丁目や番地を"-"に置き換えて、
+// "2丁目8番1号"と"2-8-1"を同じ文字列にする。数字の前の空白と末尾の"-"は取り除く。
+func streetKey(s string) string {
+	var buf strings.Builder
+	prev := false
+	for len(s) > 0 {
+		if prev {
+			m := ""
+			for _, k := range streetKeys {
+				if strings.HasPrefix(s, k) {
+					m = k
+					break
+				}
+			}
+			if m != "" {
+				buf.WriteByte('-')
+				s = s[len(m):]
+				prev = false
+				continue
+			}
+		}
+		if u := strings.TrimLeft(s, " "); len(u) < len(s) && u != "" && isDigit(rune(u[0])) {
+			s = u
+			continue
+		}
+		c := s[0]
+		prev = isDigit(rune(c))
+		buf.WriteByte(c)
+		s = s[1:]
+	}
+	return strings.TrimRight(buf.String(), "-")
+}
+
+// zipCodesはentriesの郵便番号を重複なく昇順で返す。
+func zipCodes(entries []*Entry) []Zip {
+	var a []Zip
+	seen := make(map[Zip]bool)
+	for _, entry := range entries {
+		if !seen[entry.Zip] {
+			seen[entry.Zip] = true
+			a = append(a, entry.Zip)
+		}
+	}
+	sort.Slice(a, func(i, j int) bool {
+		return a[i] < a[j]
+	})
+	return a
+}
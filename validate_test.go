@@ -0,0 +1,72 @@
+package zipcode
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	data := append([]string{
+		`16207,"938  ","9380000","ﾄﾔﾏｹﾝ","ｸﾛﾍﾞｼ","ｲｶﾆｹｲｻｲｶﾞﾅｲﾊﾞｱｲ","富山県","黒部市","以下に掲載がない場合",0,0,0,0,0,0`,
+		`16207,"93801","9380174","ﾄﾔﾏｹﾝ","ｸﾛﾍﾞｼ","ｳﾅﾂﾞｷﾏﾁｵﾄｻﾞﾜ(1-2､5)","富山県","黒部市","宇奈月町音澤（１〜２、５）",1,0,0,0,0,0`,
+		`16207,"93801","9380175","ﾄﾔﾏｹﾝ","ｸﾛﾍﾞｼ","ｳﾅﾂﾞｷﾏﾁｵﾄｻﾞﾜ(ｿﾉﾀ)","富山県","黒部市","宇奈月町音澤（その他）",1,0,0,0,0,0`,
+		`01101,"064  ","0640930","ﾎｯｶｲﾄﾞｳ","ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ","ﾐﾅﾐ30ｼﾞｮｳﾆｼ(9-11ﾁｮｳﾒ)","北海道","札幌市中央区","南三十条西（９〜１１丁目）",0,0,1,0,0,0`,
+		`01101,"060  ","0600061","ﾎｯｶｲﾄﾞｳ","ｻｯﾎﾟﾛｼﾁｭｳｵｳｸ","ﾐﾅﾐ1ｼﾞｮｳﾆｼ(1-19ﾁｮｳﾒ)","北海道","札幌市中央区","南一条西（１〜１９丁目）",0,0,1,0,0,0`,
+	}, testIndexData...)
+	idx, err := ReadIndex(strings.NewReader(strings.Join(data, "\n")))
+	if err != nil {
+		t.Fatalf("ReadIndex() = %v", err)
+	}
+	tests := []struct {
+		zip         string
+		addr        string
+		valid       bool
+		suggestions []Zip
+	}{
+		{"160-0023", "東京都新宿区西新宿2-8-1", true, nil},
+		{"1600023", "東京都 新宿区 西新宿 2-8-1 都庁", true, nil},
+		{"1600000", "新宿区西新宿二丁目8番1号", false, []Zip{"1600023"}},
+		{"1600000", "新宿区歌舞伎町1-1", true, nil},
+		{"1600023", "新宿区歌舞伎町1-1", false, []Zip{"1600000"}},
+		{"9380174", "黒部市宇奈月町音澤2番地", true, nil},
+		{"9380174", "黒部市宇奈月町音澤10番地", false, []Zip{"9380175"}},
+		{"9380175", "黒部市宇奈月町音澤", true, nil},
+		{"0640930", "札幌市中央区南三十条西10丁目", true, nil},
+		{"0640930", "札幌市中央区南三十条西", true, nil},
+		{"0640930", "札幌市中央区南30条西11-2", true, nil},
+		{"0640941", "札幌市中央区南三十条西9-1", false, []Zip{"0640930"}},
+		{"0600061", "札幌市中央区南三十条西", false, []Zip{"0640930"}},
+		{"0600061", "札幌市中央区南一条西", true, nil},
+	}
+	for _, tt := range tests {
+		v, err := idx.Validate(tt.zip, tt.addr)
+		if err != nil {
+			t.Errorf("Validate(%q, %q) = %v", tt.zip, tt.addr, err)
+			continue
+		}
+		if v.Valid != tt.valid || fmt.Sprint(v.Suggestions) != fmt.Sprint(tt.suggestions) {
+			t.Errorf("Validate(%q, %q) = %t %v; Expect %t %v", tt.zip, tt.addr, v.Valid, v.Suggestions, tt.valid, tt.suggestions)
+		}
+	}
+
+	// 南三十条西は、同じ市区町村の南一条西を範囲に含めない。
+	v, err := idx.Validate("0600061", "札幌市中央区南三十条西")
+	if err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+	for _, entry := range v.Entries {
+		if entry.Zip != "0640930" {
+			t.Errorf("Validate(%q, %q).Entries contains %s %s; Expect only 0640930", "0600061", "札幌市中央区南三十条西", entry.Zip, entry.Town.Text)
+		}
+	}
+	if len(v.Entries) != 3 {
+		t.Errorf("Validate(%q, %q).Entries = %d entries; Expect 3", "0600061", "札幌市中央区南三十条西", len(v.Entries))
+	}
+
+	_, err = idx.Validate("160", "新宿区西新宿")
+	if !errors.Is(err, errInvalidZip) || !strings.Contains(fmt.Sprint(err), `"160"`) {
+		t.Errorf("Validate(%q, %q) = %v; Expect errInvalidZip with the input", "160", "新宿区西新宿", err)
+	}
+}
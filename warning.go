@@ -0,0 +1,86 @@
+package zipcode
+
+import (
+	"fmt"
+)
+
+// 警告の種類を表す。
+type WarningKind int
+
+const (
+	// 複数行にまたがるエントリの続きが見つからない。
+	WarnIncompleteEntry WarningKind = 1
+
+	// 町域名の括弧が対応していない。
+	WarnUnbalancedBrackets WarningKind = 2
+
+	// 町域名とカナ表記で展開した要素の数が異なる。
+	WarnRubyMismatch WarningKind = 3
+
+	// 町域名に取り除けなかった注記が残っている。
+	WarnUnknownNotice WarningKind = 4
+
+	// 町域名に"その他"が残っている。
+	WarnSuspiciousOther WarningKind = 5
+
+	// 書式に合わないレコードを読み飛ばした。
+	WarnInvalidRecord WarningKind = 6
+
+	// 書式に合わないフィールドを修正した。
+	WarnRepairedRecord WarningKind = 7
+
+	// 都道府県名が全国地方公共団体コードと一致しない。
+	WarnPrefectureMismatch WarningKind = 8
+)
+
+var warningKindNames = map[WarningKind]string{
+	WarnIncompleteEntry:    "incomplete entry",
+	WarnUnbalancedBrackets: "unbalanced brackets",
+	WarnRubyMismatch:       "ruby mismatch",
+	WarnUnknownNotice:      "unknown notice",
+	WarnSuspiciousOther:    "suspicious other",
+	WarnInvalidRecord:      "invalid record",
+	WarnRepairedRecord:     "repaired record",
+	WarnPrefectureMismatch: "prefecture mismatch",
+}
+
+func (kind WarningKind) String() string {
+	if s, ok := warningKindNames[kind]; ok {
+		return s
+	}
+	return fmt.Sprintf("WarningKind(%d)", int(kind))
+}
+
+// Warningは処理を継続できる郵便番号データの異常をあらわす。
+type Warning struct {
+	// 異常の種類。
+	Kind WarningKind
+
+	// 異常が見つかったKEN_ALL.CSVの行番号。
+	Line int
+
+	// 異常が見つかったエントリ。レコードを読み飛ばした場合はnil。
+	Entry *Entry
+
+	// 異常の内容。
+	Message string
+}
+
+// newWarningはentryの複製を持つWarningを返す。
+// entryは後続のFilterで加工されるため、異常が見つかった時点の内容を保存しておく。
+func newWarning(entry *Entry, kind WarningKind, msg string) *Warning {
+	entry1 := *entry
+	return &Warning{
+		Kind:    kind,
+		Line:    entry.line,
+		Entry:   &entry1,
+		Message: msg,
+	}
+}
+
+func (w *Warning) String() string {
+	if w.Entry == nil {
+		return fmt.Sprintf("line %d: %v: %s", w.Line, w.Kind, w.Message)
+	}
+	return fmt.Sprintf("line %d: %s %s: %v: %s", w.Line, w.Entry.Zip, w.Entry.Town.Text, w.Kind, w.Message)
+}
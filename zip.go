@@ -0,0 +1,76 @@
+package zipcode
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// 郵便番号として解釈できない場合のエラー
+	errInvalidZip = errors.New("invalid zip code")
+)
+
+// Zipは郵便番号を表す。値は記号を含まない3桁、5桁または7桁の数字。
+// 3桁と5桁は1998年より前に使われていた旧郵便番号。
+type Zip string
+
+// ParseZipはsを郵便番号として解釈してZipを返す。
+// sは"1600023"の他に"160-0023"、"〒１６０−００２３"、旧郵便番号の"060"や"061-37"などの書式も受け付ける。
+func ParseZip(s string) (Zip, error) {
+	var buf strings.Builder
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			buf.WriteRune(c)
+		case c >= '０' && c <= '９':
+			buf.WriteRune(c - '０' + '0')
+		case c == '〒' || c == ' ' || c == '　' || c == '\t' || dashes[c]:
+		default:
+			return "", fmt.Errorf("%q: %w", s, errInvalidZip)
+		}
+	}
+	z := Zip(buf.String())
+	if !z.Valid() {
+		return "", fmt.Errorf("%q: %w", s, errInvalidZip)
+	}
+	return z, nil
+}
+
+// Validはzが3桁、5桁または7桁の数字かどうかを返す。
+func (z Zip) Valid() bool {
+	switch len(z) {
+	case 3, 5, 7:
+		return isDigits(string(z))
+	}
+	return false
+}
+
+// IsOldはzが旧郵便番号かどうかを返す。
+func (z Zip) IsOld() bool {
+	return len(z) < 7
+}
+
+// Areaは郵便番号の上3桁を返す。3桁未満なら空を返す。
+func (z Zip) Area() Zip {
+	if len(z) < 3 {
+		return ""
+	}
+	return z[:3]
+}
+
+// Districtは郵便番号の上5桁を返す。5桁未満なら空を返す。
+func (z Zip) District() Zip {
+	if len(z) < 5 {
+		return ""
+	}
+	return z[:5]
+}
+
+// Hyphenatedは"160-0023"や"061-37"のように、上3桁の後にハイフンを入れた郵便番号を返す。
+func (z Zip) Hyphenated() string {
+	if len(z) <= 3 {
+		return string(z)
+	}
+	return string(z[:3]) + "-" + string(z[3:])
+}
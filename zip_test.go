@@ -0,0 +1,58 @@
+package zipcode
+
+import (
+	"testing"
+)
+
+func TestParseZip(t *testing.T) {
+	tests := []struct {
+		s      string
+		expect Zip
+	}{
+		{"1600023", "1600023"},
+		{"160-0023", "1600023"},
+		{"〒160−0023", "1600023"},
+		{"〒 １６０ー００２３", "1600023"},
+		{"060", "060"},
+		{"061-37", "06137"},
+	}
+	for _, tt := range tests {
+		z, err := ParseZip(tt.s)
+		if err != nil || z != tt.expect {
+			t.Errorf("ParseZip(%q) = %q, %v; Expect %q", tt.s, z, err, tt.expect)
+		}
+	}
+	for _, s := range []string{"", "16", "160002", "16000234", "160-002a", "〒"} {
+		if z, err := ParseZip(s); err == nil {
+			t.Errorf("ParseZip(%q) = %q; Expect an error", s, z)
+		}
+	}
+}
+
+func TestZip(t *testing.T) {
+	tests := []struct {
+		z          Zip
+		area       Zip
+		district   Zip
+		hyphenated string
+		old        bool
+	}{
+		{"1600023", "160", "16000", "160-0023", false},
+		{"06137", "061", "06137", "061-37", true},
+		{"060", "060", "", "060", true},
+	}
+	for _, tt := range tests {
+		if z := tt.z.Area(); z != tt.area {
+			t.Errorf("%q.Area() = %q; Expect %q", tt.z, z, tt.area)
+		}
+		if z := tt.z.District(); z != tt.district {
+			t.Errorf("%q.District() = %q; Expect %q", tt.z, z, tt.district)
+		}
+		if s := tt.z.Hyphenated(); s != tt.hyphenated {
+			t.Errorf("%q.Hyphenated() = %q; Expect %q", tt.z, s, tt.hyphenated)
+		}
+		if old := tt.z.IsOld(); old != tt.old {
+			t.Errorf("%q.IsOld() = %t; Expect %t", tt.z, old, tt.old)
+		}
+	}
+}